@@ -47,3 +47,23 @@ type CMaper[K comparable, V any] interface {
 	Range(f func(key K, value V) bool)
 	Store(key K, value V)
 }
+
+// 区间的开闭, 零值是左闭右开[lo, hi)
+type Bound uint8
+
+const (
+	ClosedOpen Bound = iota // [lo, hi)
+	Closed                  // [lo, hi]
+	OpenClosed              // (lo, hi]
+	Open                    // (lo, hi)
+)
+
+// 是否包含左边界
+func (b Bound) IncludeLo() bool {
+	return b == ClosedOpen || b == Closed
+}
+
+// 是否包含右边界
+func (b Bound) IncludeHi() bool {
+	return b == Closed || b == OpenClosed
+}
@@ -27,6 +27,7 @@ type node[K constraints.Ordered, V any] struct {
 	parent *node[K, V]
 	pair[K, V]
 	height int
+	size   int // 子树的节点个数, Split之后O(1)得到两棵树的长度
 }
 
 // 返回左子树高度
@@ -50,6 +51,21 @@ func (n *node[K, V]) heightUpdate() {
 	lh := n.leftHeight()
 	rh := n.rightHeight()
 	n.height = cmp.Max(lh, rh) + 1
+	n.size = n.leftSize() + n.rightSize() + 1
+}
+
+func (n *node[K, V]) leftSize() int {
+	if n.left != nil {
+		return n.left.size
+	}
+	return 0
+}
+
+func (n *node[K, V]) rightSize() int {
+	if n.right != nil {
+		return n.right.size
+	}
+	return 0
 }
 
 func (n *node[K, V]) link(parent *node[K, V], link **node[K, V]) {
@@ -173,7 +189,7 @@ func (r *root[K, V]) rotateRight(node *node[K, V]) *node[K, V] {
 	r.childReplace(node, left, parent)
 	node.parent = left
 
-	return node
+	return left
 }
 
 // avl tree的结构
@@ -252,7 +268,7 @@ func (a *AvlTree[K, V]) Set(k K, v V) {
 func (a *AvlTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
 	link := &a.root.node
 	var parent *node[K, V]
-	node := &node[K, V]{pair: pair[K, V]{key: k, val: v}}
+	node := &node[K, V]{pair: pair[K, V]{key: k, val: v}, size: 1}
 
 	for *link != nil {
 		parent = *link
@@ -270,6 +286,10 @@ func (a *AvlTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
 	}
 
 	node.link(parent, link)
+	// postInsert高度不变就提前退出了, size要先一路更新到root
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
 	a.root.postInsert(node)
 	a.length++
 	return
@@ -325,6 +345,12 @@ func (a *AvlTree[K, V]) Remove(k K) *AvlTree[K, V] {
 	return a
 
 found:
+	a.erase(n)
+	return a
+}
+
+// 删除节点n
+func (a *AvlTree[K, V]) erase(n *node[K, V]) {
 	var child, parent *node[K, V]
 	if n.left != nil && n.right != nil {
 		old := n
@@ -334,7 +360,8 @@ found:
 		}
 		// 待会儿old被删除时, 使用n贴到old原来的位置
 
-		child = n.left
+		// n是old右子树里最小的节点, 只可能有右孩子
+		child = n.right
 		parent = n.parent
 		if child != nil {
 			// child 这条线不再n 节点
@@ -352,6 +379,7 @@ found:
 		n.right = old.right
 		n.parent = old.parent
 		n.height = old.height
+		n.size = old.size
 
 		a.root.childReplace(old, n, old.parent)
 		old.left.parent = n
@@ -372,10 +400,14 @@ found:
 		}
 	}
 
+	for p := parent; p != nil; p = p.parent {
+		p.size--
+	}
+
 	if parent != nil {
 		a.root.rebalance(parent)
 	}
-	return a
+	a.length--
 }
 
 func (n *node[K, V]) rangeInner(callback func(k K, v V) bool) bool {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/antlabs/gstl/cmp"
 	"github.com/antlabs/gstl/vec"
+	"golang.org/x/exp/constraints"
 )
 
 // 从小到大, 插入
@@ -321,3 +322,67 @@ func equalSlices(a, b []int) bool {
 	}
 	return true
 }
+
+// 检查每个节点的height和平衡因子, 返回子树高度
+func checkBalance[K constraints.Ordered, V any](t *testing.T, n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	lh, rh := checkBalance(t, n.left), checkBalance(t, n.right)
+	if n.height != cmp.Max(lh, rh)+1 {
+		t.Fatalf("key %v height %d, want %d", n.key, n.height, cmp.Max(lh, rh)+1)
+	}
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("key %v unbalanced, left %d right %d", n.key, lh, rh)
+	}
+	return n.height
+}
+
+// 右旋要返回新的子树根, 不然双旋之后高度更新到错误的节点上
+func Test_AVLTree_RotateRight(t *testing.T) {
+	b := New[int, int]()
+	for i := 1000; i > 0; i-- {
+		b.Set(i, i)
+	}
+	checkBalance(t, b.root.node)
+
+	// 先插大再插小, 触发先右旋再左旋
+	b = New[int, int]()
+	for _, k := range []int{10, 30, 20, 40, 35, 50, 45} {
+		b.Set(k, k)
+		checkBalance(t, b.root.node)
+	}
+}
+
+// 删除有两个孩子的节点, 后继节点的右子树不能丢
+func Test_AVLTree_DeleteTwoChildren(t *testing.T) {
+	for del := 0; del < 100; del++ {
+		b := New[int, int]()
+		for i := 0; i < 100; i++ {
+			b.Set(i, i)
+		}
+
+		b.Delete(del)
+		checkBalance(t, b.root.node)
+		for k := 0; k < 100; k++ {
+			if _, ok := b.TryGet(k); ok != (k != del) {
+				t.Fatalf("after delete %d, key %d found %v", del, k, ok)
+			}
+		}
+	}
+}
+
+// 删除存在的key长度减1, 删除不存在的key长度不变
+func Test_Delete_Len(t *testing.T) {
+	b := New[int, int]()
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+	for i := 0; i < 100; i++ {
+		b.Delete(i)
+		b.Delete(i)
+		if b.Len() != 99-i {
+			t.Fatalf("after delete %d, len %d, want %d", i, b.Len(), 99-i)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package avltree
+
+// apache 2.0 antlabs
+import "golang.org/x/exp/constraints"
+
+// 游标, 可以暂停, 恢复, 也可以从任意key开始双向遍历
+// 除了Cursor.Delete, 其他对树的修改都会让游标失效, 需要重新Seek
+type Cursor[K constraints.Ordered, V any] struct {
+	t *AvlTree[K, V]
+	n *node[K, V]
+}
+
+// 返回一个未定位的游标, 使用前需要先调用Seek, SeekFirst或者SeekLast
+func (a *AvlTree[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{t: a}
+}
+
+// 定位到第一个大于等于k的元素
+func (c *Cursor[K, V]) Seek(k K) bool {
+	c.n = c.t.root.lowerBound(k)
+	return c.n != nil
+}
+
+// 定位到最小的元素
+func (c *Cursor[K, V]) SeekFirst() bool {
+	c.n = c.t.root.first()
+	return c.n != nil
+}
+
+// 定位到最大的元素
+func (c *Cursor[K, V]) SeekLast() bool {
+	c.n = c.t.root.last()
+	return c.n != nil
+}
+
+// 移到下一个元素, 没有下一个元素时游标失效
+func (c *Cursor[K, V]) Next() bool {
+	if c.n == nil {
+		return false
+	}
+	c.n = c.n.next()
+	return c.n != nil
+}
+
+// 移到上一个元素, 没有上一个元素时游标失效
+func (c *Cursor[K, V]) Prev() bool {
+	if c.n == nil {
+		return false
+	}
+	c.n = c.n.prev()
+	return c.n != nil
+}
+
+// 游标是否指向一个元素
+func (c *Cursor[K, V]) Valid() bool {
+	return c.n != nil
+}
+
+// 当前元素的key, 游标失效时返回零值
+func (c *Cursor[K, V]) Key() (k K) {
+	if c.n == nil {
+		return
+	}
+	return c.n.key
+}
+
+// 当前元素的value, 游标失效时返回零值
+func (c *Cursor[K, V]) Value() (v V) {
+	if c.n == nil {
+		return
+	}
+	return c.n.val
+}
+
+// 删除当前元素, 游标移到下一个元素
+// erase只调整节点之间的指针, 不会搬动pair, 所以提前保存的后继节点依旧有效
+func (c *Cursor[K, V]) Delete() {
+	if c.n == nil {
+		return
+	}
+
+	next := c.n.next()
+	c.t.erase(c.n)
+	c.n = next
+}
+
+// 返回第一个大于等于k的节点
+func (r *root[K, V]) lowerBound(k K) (rv *node[K, V]) {
+	n := r.node
+	for n != nil {
+		if n.key >= k {
+			rv = n
+			n = n.left
+		} else {
+			n = n.right
+		}
+	}
+	return
+}
+
+// 返回最小的节点
+func (r *root[K, V]) first() *node[K, V] {
+	n := r.node
+	if n == nil {
+		return nil
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+// 返回最大的节点
+func (r *root[K, V]) last() *node[K, V] {
+	n := r.node
+	if n == nil {
+		return nil
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// 中序遍历的后继节点
+func (n *node[K, V]) next() *node[K, V] {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
+// 中序遍历的前驱节点
+func (n *node[K, V]) prev() *node[K, V] {
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+
+	for n.parent != nil && n == n.parent.left {
+		n = n.parent
+	}
+	return n.parent
+}
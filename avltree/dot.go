@@ -0,0 +1,41 @@
+package avltree
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+	"io"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// 输出graphviz dot格式, 每个节点带上高度
+// opts为nil时使用默认的选项
+func (a *AvlTree[K, V]) WriteDOT(w io.Writer, opts *dot.Options[K, V]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph avltree {")
+	if a.root.node != nil {
+		a.root.node.writeDOT(dw, 0)
+	}
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 返回节点名
+func (n *node[K, V]) writeDOT(w *idot.Writer[K, V], depth int) string {
+	if w.Truncated(depth) {
+		return w.Ellipsis()
+	}
+
+	id := w.NextID()
+	label := fmt.Sprintf("%s\nh=%d", w.Label(n.key, n.val), n.height)
+	w.Printf("\t%s [label=%s];", id, idot.Quote(label))
+
+	if n.left != nil {
+		w.Printf("\t%s -> %s [label=L];", id, n.left.writeDOT(w, depth+1))
+	}
+	if n.right != nil {
+		w.Printf("\t%s -> %s [label=R];", id, n.right.writeDOT(w, depth+1))
+	}
+	return id
+}
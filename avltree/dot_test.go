@@ -0,0 +1,38 @@
+package avltree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+func Test_AvlTree_WriteDOT(t *testing.T) {
+	a := New[int, int]()
+	for i := 0; i < 7; i++ {
+		a.Set(i, i)
+	}
+
+	var buf bytes.Buffer
+	if err := a.WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph avltree {") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	// 7个节点的完全二叉树, root高度是3
+	if !strings.Contains(out, `"3\nh=3"`) || strings.Count(out, "->") != 6 {
+		t.Fatalf("bad output:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := a.WriteDOT(&buf, &dot.Options[int, int]{MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), `"..."`); n != 2 {
+		t.Fatalf("got %d ellipsis:\n%s", n, buf.String())
+	}
+}
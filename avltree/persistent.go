@@ -0,0 +1,391 @@
+package avltree
+
+// apache 2.0 antlabs
+
+// 参考资料
+// https://en.wikipedia.org/wiki/Persistent_data_structure#Path_copying
+// https://clojure.org/reference/transients
+import (
+	"github.com/antlabs/gstl/cmp"
+	"golang.org/x/exp/constraints"
+)
+
+// 持久化avl tree
+// 每次Set/Delete只复制从root到修改位置这条路径上的节点, 其他子树新老版本共享
+// 老版本不会被修改, 多个goroutine读老版本不需要加锁
+type Persistent[K constraints.Ordered, V any] struct {
+	root   *pnode[K, V]
+	length int
+}
+
+// 批量修改模式, 自己创建的节点直接原地修改, 不用每次都复制路径
+// Transient不是并发安全的, 用完之后调用Persistent()得到一个不可变的版本
+type Transient[K constraints.Ordered, V any] struct {
+	root   *pnode[K, V]
+	length int
+	owner  *owner[K, V]
+}
+
+// 节点的所有者, 只有所有者才能原地修改节点
+// 不能是空结构体, 空结构体的指针可能相同
+type owner[K constraints.Ordered, V any] struct {
+	_ byte
+}
+
+// 持久化的节点没有parent指针, 否则共享子树的时候没法复制路径
+type pnode[K constraints.Ordered, V any] struct {
+	left  *pnode[K, V]
+	right *pnode[K, V]
+	pair[K, V]
+	height int
+	owner  *owner[K, V]
+}
+
+func (n *pnode[K, V]) leftHeight() int {
+	if n.left != nil {
+		return n.left.height
+	}
+	return 0
+}
+
+func (n *pnode[K, V]) rightHeight() int {
+	if n.right != nil {
+		return n.right.height
+	}
+	return 0
+}
+
+func (n *pnode[K, V]) heightUpdate() {
+	n.height = cmp.Max(n.leftHeight(), n.rightHeight()) + 1
+}
+
+// 节点属于o就直接返回, 否则复制一份属于o的节点
+func (o *owner[K, V]) mut(n *pnode[K, V]) *pnode[K, V] {
+	if n.owner == o {
+		return n
+	}
+
+	c := *n
+	c.owner = o
+	return &c
+}
+
+// 右旋, n必须已经属于o
+func (o *owner[K, V]) rotateRight(n *pnode[K, V]) *pnode[K, V] {
+	left := o.mut(n.left)
+	n.left = left.right
+	left.right = n
+	n.heightUpdate()
+	left.heightUpdate()
+	return left
+}
+
+// 左旋, n必须已经属于o
+func (o *owner[K, V]) rotateLeft(n *pnode[K, V]) *pnode[K, V] {
+	right := o.mut(n.right)
+	n.right = right.left
+	right.left = n
+	n.heightUpdate()
+	right.heightUpdate()
+	return right
+}
+
+// 重新计算高度, 失衡就旋转, 返回子树新的根
+func (o *owner[K, V]) balance(n *pnode[K, V]) *pnode[K, V] {
+	lh, rh := n.leftHeight(), n.rightHeight()
+	switch diff := lh - rh; {
+	case diff >= 2:
+		if n.left.leftHeight() < n.left.rightHeight() {
+			n.left = o.rotateLeft(o.mut(n.left))
+		}
+		return o.rotateRight(n)
+	case diff <= -2:
+		if n.right.leftHeight() > n.right.rightHeight() {
+			n.right = o.rotateRight(o.mut(n.right))
+		}
+		return o.rotateLeft(n)
+	}
+
+	n.heightUpdate()
+	return n
+}
+
+func (o *owner[K, V]) insert(n *pnode[K, V], k K, v V) (rv *pnode[K, V], prev V, replaced bool) {
+	if n == nil {
+		return &pnode[K, V]{pair: pair[K, V]{key: k, val: v}, height: 1, owner: o}, prev, false
+	}
+
+	n = o.mut(n)
+	switch {
+	case k < n.key:
+		n.left, prev, replaced = o.insert(n.left, k, v)
+	case k > n.key:
+		n.right, prev, replaced = o.insert(n.right, k, v)
+	default:
+		prev = n.val
+		n.val = v
+		return n, prev, true
+	}
+
+	if replaced {
+		return n, prev, true
+	}
+	return o.balance(n), prev, false
+}
+
+// 删除子树里最小的节点, 返回新的子树和被删除的节点
+func (o *owner[K, V]) deleteMin(n *pnode[K, V]) (rv *pnode[K, V], min *pnode[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+
+	n = o.mut(n)
+	n.left, min = o.deleteMin(n.left)
+	return o.balance(n), min
+}
+
+func (o *owner[K, V]) remove(n *pnode[K, V], k K) (rv *pnode[K, V], prev V, deleted bool) {
+	if n == nil {
+		return nil, prev, false
+	}
+
+	var child *pnode[K, V]
+	switch {
+	case k < n.key:
+		if child, prev, deleted = o.remove(n.left, k); !deleted {
+			return n, prev, false
+		}
+		n = o.mut(n)
+		n.left = child
+	case k > n.key:
+		if child, prev, deleted = o.remove(n.right, k); !deleted {
+			return n, prev, false
+		}
+		n = o.mut(n)
+		n.right = child
+	default:
+		prev = n.val
+		if n.left == nil {
+			return n.right, prev, true
+		}
+		if n.right == nil {
+			return n.left, prev, true
+		}
+
+		// 用右子树最小的节点代替n
+		right, min := o.deleteMin(n.right)
+		n = o.mut(n)
+		n.pair = min.pair
+		n.right = right
+	}
+
+	return o.balance(n), prev, true
+}
+
+func (n *pnode[K, V]) get(k K) (v V, ok bool) {
+	for n != nil {
+		if n.key == k {
+			return n.val, true
+		}
+
+		if k > n.key {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return
+}
+
+func (n *pnode[K, V]) rangeInner(callback func(k K, v V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if !n.left.rangeInner(callback) {
+		return false
+	}
+
+	if !callback(n.key, n.val) {
+		return false
+	}
+
+	return n.right.rangeInner(callback)
+}
+
+func (n *pnode[K, V]) rangePrevInner(callback func(k K, v V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if !n.right.rangePrevInner(callback) {
+		return false
+	}
+
+	if !callback(n.key, n.val) {
+		return false
+	}
+
+	return n.left.rangePrevInner(callback)
+}
+
+// 构造函数
+func NewPersistent[K constraints.Ordered, V any]() *Persistent[K, V] {
+	return &Persistent[K, V]{}
+}
+
+// 设置, 返回新的版本, 原来的版本不变
+func (p *Persistent[K, V]) Set(k K, v V) *Persistent[K, V] {
+	// 每次修改用一个新的所有者, 这次修改新建的节点旋转时可以原地修改, 不会复制两次
+	root, _, replaced := new(owner[K, V]).insert(p.root, k, v)
+	length := p.length
+	if !replaced {
+		length++
+	}
+	return &Persistent[K, V]{root: root, length: length}
+}
+
+// 删除, 返回新的版本, 原来的版本不变
+// 如果k不存在, 返回p本身
+func (p *Persistent[K, V]) Delete(k K) *Persistent[K, V] {
+	root, _, deleted := new(owner[K, V]).remove(p.root, k)
+	if !deleted {
+		return p
+	}
+	return &Persistent[K, V]{root: root, length: p.length - 1}
+}
+
+// 获取
+func (p *Persistent[K, V]) Get(k K) (v V) {
+	v, _ = p.root.get(k)
+	return
+}
+
+// 获取, 找到ok为true
+func (p *Persistent[K, V]) TryGet(k K) (v V, ok bool) {
+	return p.root.get(k)
+}
+
+// 返回元素个数
+func (p *Persistent[K, V]) Len() int {
+	return p.length
+}
+
+// 第一个节点
+func (p *Persistent[K, V]) First() (v V, ok bool) {
+	n := p.root
+	if n == nil {
+		return
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+	return n.val, true
+}
+
+// 最后一个节点
+func (p *Persistent[K, V]) Last() (v V, ok bool) {
+	n := p.root
+	if n == nil {
+		return
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+	return n.val, true
+}
+
+// 升序遍历
+func (p *Persistent[K, V]) Range(callback func(k K, v V) bool) {
+	p.root.rangeInner(callback)
+}
+
+// 降序遍历
+func (p *Persistent[K, V]) RangePrev(callback func(k K, v V) bool) {
+	p.root.rangePrevInner(callback)
+}
+
+// 返回最小的limit个值, 升序
+func (p *Persistent[K, V]) TopMin(limit int, callback func(k K, v V) bool) {
+	p.Range(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+// 返回最大的limit个值, 降序
+func (p *Persistent[K, V]) TopMax(limit int, callback func(k K, v V) bool) {
+	p.RangePrev(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+// 进入批量修改模式, O(1), p本身不会被修改
+func (p *Persistent[K, V]) Transient() *Transient[K, V] {
+	return &Transient[K, V]{root: p.root, length: p.length, owner: new(owner[K, V])}
+}
+
+// 设置
+func (t *Transient[K, V]) Set(k K, v V) {
+	_, _ = t.Swap(k, v)
+}
+
+// 设置, 如果有值, 把prev值带返回
+func (t *Transient[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	t.root, prev, replaced = t.owner.insert(t.root, k, v)
+	if !replaced {
+		t.length++
+	}
+	return
+}
+
+// 删除
+func (t *Transient[K, V]) Delete(k K) {
+	var deleted bool
+	t.root, _, deleted = t.owner.remove(t.root, k)
+	if deleted {
+		t.length--
+	}
+}
+
+// 获取
+func (t *Transient[K, V]) Get(k K) (v V) {
+	v, _ = t.root.get(k)
+	return
+}
+
+// 获取, 找到ok为true
+func (t *Transient[K, V]) TryGet(k K) (v V, ok bool) {
+	return t.root.get(k)
+}
+
+// 返回元素个数
+func (t *Transient[K, V]) Len() int {
+	return t.length
+}
+
+// 返回一个不可变的版本, O(1)
+// 之后t换一个新的所有者, 继续修改t不会影响返回的版本
+func (t *Transient[K, V]) Persistent() *Persistent[K, V] {
+	t.owner = new(owner[K, V])
+	return &Persistent[K, V]{root: t.root, length: t.length}
+}
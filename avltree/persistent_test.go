@@ -0,0 +1,212 @@
+package avltree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// 检查高度和平衡因子
+func checkPersistent(t *testing.T, n *pnode[int, int]) int {
+	if n == nil {
+		return 0
+	}
+
+	lh := checkPersistent(t, n.left)
+	rh := checkPersistent(t, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("key:%d unbalanced, left:%d right:%d", n.key, lh, rh)
+	}
+
+	h := lh + 1
+	if rh > lh {
+		h = rh + 1
+	}
+	if n.height != h {
+		t.Fatalf("key:%d Expected height %d, got %d", n.key, h, n.height)
+	}
+	return h
+}
+
+func persistentToMap(p *Persistent[int, int]) map[int]int {
+	m := map[int]int{}
+	p.Range(func(k, v int) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
+
+func equalMap(a, b map[int]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if v2, ok := b[k]; !ok || v2 != v {
+			return false
+		}
+	}
+	return true
+}
+
+// 每个版本都保存一份快照, 修改之后老版本不能变
+func Test_Persistent_Versions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	p := NewPersistent[int, int]()
+	m := map[int]int{}
+
+	var versions []*Persistent[int, int]
+	var snapshots []map[int]int
+	for i := 0; i < 2000; i++ {
+		k := r.Intn(300)
+		if r.Intn(3) == 0 {
+			p = p.Delete(k)
+			delete(m, k)
+		} else {
+			p = p.Set(k, i)
+			m[k] = i
+		}
+
+		if p.Len() != len(m) {
+			t.Fatalf("Expected %d, got %d", len(m), p.Len())
+		}
+
+		if i%50 == 0 {
+			checkPersistent(t, p.root)
+			snap := map[int]int{}
+			for k, v := range m {
+				snap[k] = v
+			}
+			versions = append(versions, p)
+			snapshots = append(snapshots, snap)
+		}
+	}
+
+	for i, v := range versions {
+		if !equalMap(persistentToMap(v), snapshots[i]) {
+			t.Fatalf("version %d changed", i)
+		}
+		if v.Len() != len(snapshots[i]) {
+			t.Fatalf("version %d Expected %d, got %d", i, len(snapshots[i]), v.Len())
+		}
+	}
+}
+
+// 读接口
+func Test_Persistent_Read(t *testing.T) {
+	p := NewPersistent[int, int]()
+	if _, ok := p.First(); ok {
+		t.Errorf("Expected false, got true")
+	}
+
+	for i := 0; i < 100; i++ {
+		p = p.Set(i, i*10)
+	}
+
+	if v := p.Get(10); v != 100 {
+		t.Errorf("Expected 100, got %d", v)
+	}
+	if _, ok := p.TryGet(100); ok {
+		t.Errorf("Expected false, got true")
+	}
+	if v, _ := p.First(); v != 0 {
+		t.Errorf("Expected 0, got %d", v)
+	}
+	if v, _ := p.Last(); v != 990 {
+		t.Errorf("Expected 990, got %d", v)
+	}
+
+	var got []int
+	p.TopMax(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !equalSlices(got, []int{99, 98, 97}) {
+		t.Errorf("Expected [99 98 97], got %v", got)
+	}
+
+	got = got[:0]
+	p.TopMin(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !equalSlices(got, []int{0, 1, 2}) {
+		t.Errorf("Expected [0 1 2], got %v", got)
+	}
+
+	// 删除不存在的key, 返回原来的版本
+	if p.Delete(1000) != p {
+		t.Errorf("Expected same version")
+	}
+}
+
+// 批量修改模式
+func Test_Persistent_Transient(t *testing.T) {
+	base := NewPersistent[int, int]().Set(-1, -1)
+
+	tr := base.Transient()
+	for i := 0; i < 1000; i++ {
+		tr.Set(i, i)
+	}
+	for i := 0; i < 1000; i += 2 {
+		tr.Delete(i)
+	}
+
+	p1 := tr.Persistent()
+	checkPersistent(t, p1.root)
+	if p1.Len() != 501 {
+		t.Errorf("Expected 501, got %d", p1.Len())
+	}
+	if base.Len() != 1 {
+		t.Errorf("Expected 1, got %d", base.Len())
+	}
+
+	// Persistent()之后继续修改transient, 不影响p1
+	tr.Set(1, 100)
+	tr.Delete(3)
+	if v := p1.Get(1); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	if _, ok := p1.TryGet(3); !ok {
+		t.Errorf("Expected true, got false")
+	}
+	if v := tr.Get(1); v != 100 {
+		t.Errorf("Expected 100, got %d", v)
+	}
+	if tr.Len() != 500 {
+		t.Errorf("Expected 500, got %d", tr.Len())
+	}
+}
+
+// 写新版本的同时并发读老版本
+func Test_Persistent_ConcurrentRead(t *testing.T) {
+	p := NewPersistent[int, int]()
+	for i := 0; i < 1000; i++ {
+		p = p.Set(i, i)
+	}
+
+	old := p
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			p = p.Set(i, -i).Delete(i / 2)
+		}
+	}()
+
+	for j := 0; j < 10; j++ {
+		n := 0
+		old.Range(func(k, v int) bool {
+			if k != v {
+				t.Errorf("Expected %d, got %d", k, v)
+			}
+			n++
+			return true
+		})
+		if n != 1000 {
+			t.Errorf("Expected 1000, got %d", n)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,54 @@
+package avltree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+)
+
+// 升序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+// 直接从lo开始, 不需要从头遍历
+func (a *AvlTree[K, V]) RangeBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.Between[K, V](a.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 降序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+func (a *AvlTree[K, V]) RangePrevBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.PrevBetween[K, V](a.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 删除[lo, hi)区间的所有元素, 返回删除的个数, 可以通过bound修改区间的开闭
+// 用split把区间整个切下来, 再join剩下的两棵树, O(log n), 和删除的个数无关
+func (a *AvlTree[K, V]) DeleteRange(lo, hi K, bound ...api.Bound) (n int) {
+	bd := rangeop.GetBound(bound)
+	if lo > hi || lo == hi && (!bd.IncludeLo() || !bd.IncludeHi()) {
+		return 0
+	}
+
+	left, rest, l := split(a.reset(), lo)
+	if l != nil {
+		if bd.IncludeLo() {
+			n++
+		} else {
+			left = join(left, l, nil)
+		}
+	}
+
+	mid, right, h := split(rest, hi)
+	if mid != nil {
+		n += mid.size
+	}
+	if h != nil {
+		if bd.IncludeHi() {
+			n++
+		} else {
+			right = join(nil, h, right)
+		}
+	}
+
+	a.root.node = join2(left, right)
+	if a.root.node != nil {
+		a.length = a.root.node.size
+	}
+	return n
+}
@@ -0,0 +1,57 @@
+package avltree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/internal/bulk"
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	ErrNotSorted    = bulk.ErrNotSorted
+	ErrDuplicateKey = bulk.ErrDuplicateKey
+	ErrLenMismatch  = bulk.ErrLenMismatch
+)
+
+// 从升序的keys, vals构造avl树, O(n)
+// keys不是升序或者有重复的key会返回错误
+func FromSorted[K constraints.Ordered, V any](keys []K, vals []V) (*AvlTree[K, V], error) {
+	f, err := bulk.Slices(keys, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSortedFunc(len(keys), f)
+}
+
+// 从升序的n个元素构造avl树, O(n), f按0到n-1的顺序调用
+// key不是升序或者有重复的key会返回错误, 发现错误之后不会再调用f
+func FromSortedFunc[K constraints.Ordered, V any](n int, f func(i int) (K, V)) (*AvlTree[K, V], error) {
+	a := New[K, V]()
+	if n <= 0 {
+		return a, nil
+	}
+
+	it := bulk.NewIter(f)
+	a.root.node = buildSorted(it, nil, 0, n)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	a.length = n
+	return a, nil
+}
+
+// 中序构造[lo, hi)区间的子树, 左右子树的元素个数最多差1, 高度也最多差1
+func buildSorted[K constraints.Ordered, V any](it *bulk.Iter[K, V], parent *node[K, V], lo, hi int) *node[K, V] {
+	if lo >= hi || it.Err() != nil {
+		return nil
+	}
+
+	mid := lo + (hi-lo)/2
+	n := &node[K, V]{parent: parent}
+	n.left = buildSorted(it, n, lo, mid)
+	n.key, n.val = it.Next()
+	n.right = buildSorted(it, n, mid+1, hi)
+	n.heightUpdate()
+	return n
+}
@@ -0,0 +1,105 @@
+package avltree
+
+import (
+	"testing"
+)
+
+// 检查高度, size和parent指针, 返回子树高度
+func checkAvl(t *testing.T, n *node[int, int]) int {
+	if n == nil {
+		return 0
+	}
+
+	for _, c := range []*node[int, int]{n.left, n.right} {
+		if c != nil && c.parent != n {
+			t.Fatalf("node %d: wrong parent", c.key)
+		}
+	}
+
+	lh, rh := checkAvl(t, n.left), checkAvl(t, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("node %d: unbalanced %d %d", n.key, lh, rh)
+	}
+	h := lh + 1
+	if rh > lh {
+		h = rh + 1
+	}
+	if n.height != h {
+		t.Fatalf("node %d: height %d, want %d", n.key, n.height, h)
+	}
+	if n.size != n.leftSize()+n.rightSize()+1 {
+		t.Fatalf("node %d: wrong size %d", n.key, n.size)
+	}
+	return h
+}
+
+func Test_AvlTree_FromSorted(t *testing.T) {
+	for n := 0; n < 300; n++ {
+		keys := make([]int, n)
+		vals := make([]int, n)
+		for i := range keys {
+			keys[i] = i * 2
+			vals[i] = i
+		}
+
+		a, err := FromSorted(keys, vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Len() != n {
+			t.Fatalf("n:%d, len:%d", n, a.Len())
+		}
+		checkAvl(t, a.root.node)
+
+		i := 0
+		a.Range(func(k, v int) bool {
+			if k != keys[i] || v != vals[i] {
+				t.Fatalf("n:%d, index %d: got (%d, %d)", n, i, k, v)
+			}
+			i++
+			return true
+		})
+
+		if n == 0 {
+			continue
+		}
+
+		// 构造好的树可以继续修改
+		a.Set(1, 1)
+		a.Delete(0)
+		checkAvl(t, a.root.node)
+		if _, ok := a.TryGet(1); !ok || a.Len() != n {
+			t.Fatalf("n:%d, set/delete after FromSorted failed", n)
+		}
+	}
+}
+
+func Test_AvlTree_FromSorted_Error(t *testing.T) {
+	if _, err := FromSorted([]int{1, 3, 2}, []int{1, 2, 3}); err != ErrNotSorted {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2, 2}, []int{1, 2, 3}); err != ErrDuplicateKey {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2}, []int{1}); err != ErrLenMismatch {
+		t.Fatalf("got %v", err)
+	}
+
+	a, err := FromSortedFunc(100, func(i int) (int, string) { return i, "" })
+	if err != nil || a.Len() != 100 {
+		t.Fatalf("len:%d, err:%v", a.Len(), err)
+	}
+
+	// 发现乱序之后不再调用f
+	calls := 0
+	_, err = FromSortedFunc(100, func(i int) (int, int) {
+		calls++
+		if i == 10 {
+			return 0, i
+		}
+		return i, i
+	})
+	if err != ErrNotSorted || calls != 11 {
+		t.Fatalf("err:%v, calls:%d", err, calls)
+	}
+}
@@ -0,0 +1,247 @@
+package avltree
+
+// apache 2.0 antlabs
+
+// 参考资料
+// Just Join for Parallel Ordered Sets https://arxiv.org/abs/1602.02120
+import (
+	"errors"
+
+	"golang.org/x/exp/constraints"
+)
+
+var ErrOverlap = errors.New("avltree: key ranges overlap")
+
+func height[K constraints.Ordered, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// 设置左右孩子, 重新计算高度和size
+func (n *node[K, V]) setChildren(left, right *node[K, V]) *node[K, V] {
+	n.left, n.right = left, right
+	if left != nil {
+		left.parent = n
+	}
+	if right != nil {
+		right.parent = n
+	}
+	n.heightUpdate()
+	return n
+}
+
+// 把n从树上摘下来, 返回左右子树, 子树的parent都置空
+func (n *node[K, V]) expose() (left, right *node[K, V]) {
+	left, right = n.left, n.right
+	if left != nil {
+		left.parent = nil
+	}
+	if right != nil {
+		right.parent = nil
+	}
+	n.left, n.right, n.parent = nil, nil, nil
+	return
+}
+
+func rotateLeftNode[K constraints.Ordered, V any](n *node[K, V]) *node[K, V] {
+	right := n.right
+	n.setChildren(n.left, right.left)
+	return right.setChildren(n, right.right)
+}
+
+func rotateRightNode[K constraints.Ordered, V any](n *node[K, V]) *node[K, V] {
+	left := n.left
+	n.setChildren(left.right, n.right)
+	return left.setChildren(left.left, n)
+}
+
+// left里所有的key < m.key < right里所有的key, 合并成一棵树, O(|h(left)-h(right)|)
+func join[K constraints.Ordered, V any](left, m, right *node[K, V]) (rv *node[K, V]) {
+	switch lh, rh := height(left), height(right); {
+	case lh > rh+1:
+		rv = joinRight(left, m, right)
+	case rh > lh+1:
+		rv = joinLeft(left, m, right)
+	default:
+		rv = m.setChildren(left, right)
+	}
+	rv.parent = nil
+	return rv
+}
+
+// left比right高, 沿着left的右边往下找到高度差不多的子树, 把m和right挂上去
+func joinRight[K constraints.Ordered, V any](left, m, right *node[K, V]) *node[K, V] {
+	c := left.right
+	if height(c) <= height(right)+1 {
+		t := m.setChildren(c, right)
+		if t.height <= height(left.left)+1 {
+			return left.setChildren(left.left, t)
+		}
+		return rotateLeftNode(left.setChildren(left.left, rotateRightNode(t)))
+	}
+
+	t := joinRight(c, m, right)
+	left.setChildren(left.left, t)
+	if t.height <= height(left.left)+1 {
+		return left
+	}
+	return rotateLeftNode(left)
+}
+
+// 和joinRight对称
+func joinLeft[K constraints.Ordered, V any](left, m, right *node[K, V]) *node[K, V] {
+	c := right.left
+	if height(c) <= height(left)+1 {
+		t := m.setChildren(left, c)
+		if t.height <= height(right.right)+1 {
+			return right.setChildren(t, right.right)
+		}
+		return rotateRightNode(right.setChildren(rotateLeftNode(t), right.right))
+	}
+
+	t := joinLeft(left, m, c)
+	right.setChildren(t, right.right)
+	if t.height <= height(right.right)+1 {
+		return right
+	}
+	return rotateRightNode(right)
+}
+
+// 摘掉最小的节点, 返回剩下的树和最小的节点
+func splitMin[K constraints.Ordered, V any](n *node[K, V]) (rest, min *node[K, V]) {
+	left, right := n.expose()
+	if left == nil {
+		return right, n
+	}
+
+	rest, min = splitMin(left)
+	return join(rest, n, right), min
+}
+
+// 没有中间节点的join, left里所有的key < right里所有的key
+func join2[K constraints.Ordered, V any](left, right *node[K, V]) *node[K, V] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	rest, min := splitMin(right)
+	return join(left, min, rest)
+}
+
+// 按k把n分成小于k和大于k的两棵树, found是key等于k的节点
+func split[K constraints.Ordered, V any](n *node[K, V], k K) (left, right, found *node[K, V]) {
+	if n == nil {
+		return nil, nil, nil
+	}
+
+	l, r := n.expose()
+	switch {
+	case k < n.key:
+		left, right, found = split(l, k)
+		return left, join(right, n, r), found
+	case k > n.key:
+		left, right, found = split(r, k)
+		return join(l, n, left), right, found
+	}
+	return l, r, n
+}
+
+func union[K constraints.Ordered, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	al, ar := a.expose()
+	bl, br, _ := split(b, a.key)
+	return join(union(al, bl), a, union(ar, br))
+}
+
+func intersection[K constraints.Ordered, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	al, ar := a.expose()
+	bl, br, found := split(b, a.key)
+	left, right := intersection(al, bl), intersection(ar, br)
+	if found != nil {
+		return join(left, a, right)
+	}
+	return join2(left, right)
+}
+
+func difference[K constraints.Ordered, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil || b == nil {
+		return a
+	}
+
+	bl, br := b.expose()
+	al, ar, _ := split(a, b.key)
+	return join2(difference(al, bl), difference(ar, br))
+}
+
+// 用root节点新建一棵树
+func fromRoot[K constraints.Ordered, V any](n *node[K, V]) *AvlTree[K, V] {
+	a := New[K, V]()
+	a.root.node = n
+	if n != nil {
+		n.parent = nil
+		a.length = n.size
+	}
+	return a
+}
+
+// 清空, 节点已经被新的树复用
+func (a *AvlTree[K, V]) reset() *node[K, V] {
+	n := a.root.node
+	a.root.node = nil
+	a.length = 0
+	return n
+}
+
+// 按k把树分成两棵, left里的key都小于k, right里的key都大于k, O(log n)
+// 如果k存在, 通过found返回它的值, ok为true
+// Split之后a变成空树, 节点被left和right复用
+func (a *AvlTree[K, V]) Split(k K) (left, right *AvlTree[K, V], found V, ok bool) {
+	l, r, f := split(a.reset(), k)
+	if f != nil {
+		found, ok = f.val, true
+	}
+	return fromRoot(l), fromRoot(r), found, ok
+}
+
+// 合并两棵树, left里的key必须都小于right里的key, 否则返回ErrOverlap, O(log n)
+// 合并之后left和right变成空树
+func Join[K constraints.Ordered, V any](left, right *AvlTree[K, V]) (*AvlTree[K, V], error) {
+	if left.root.node != nil && right.root.node != nil && left.root.last().key >= right.root.first().key {
+		return nil, ErrOverlap
+	}
+
+	return fromRoot(join2(left.reset(), right.reset())), nil
+}
+
+// 并集, 相同的key保留a的值, O(m log(n/m+1)), m是小树的元素个数
+// 会消耗a和b: 节点直接拿来拼结果, 调用之后a和b都是空树, 还要用原来的数据请先复制一份
+func Union[K constraints.Ordered, V any](a, b *AvlTree[K, V]) *AvlTree[K, V] {
+	return fromRoot(union(a.reset(), b.reset()))
+}
+
+// 交集, 保留a的值, O(m log(n/m+1))
+// 会消耗a和b: 调用之后a和b都是空树, 不在结果里的节点直接丢弃
+func Intersection[K constraints.Ordered, V any](a, b *AvlTree[K, V]) *AvlTree[K, V] {
+	return fromRoot(intersection(a.reset(), b.reset()))
+}
+
+// 差集a-b, O(m log(n/m+1))
+// 会消耗a和b: 调用之后a和b都是空树, 不在结果里的节点直接丢弃
+func Difference[K constraints.Ordered, V any](a, b *AvlTree[K, V]) *AvlTree[K, V] {
+	return fromRoot(difference(a.reset(), b.reset()))
+}
@@ -0,0 +1,244 @@
+package avltree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+)
+
+// 检查树的结构, 并且和map里的内容一致
+func checkTreeMap(t *testing.T, a *AvlTree[int, int], m map[int]int) {
+	t.Helper()
+	if a.root.node != nil {
+		if a.root.node.parent != nil {
+			t.Fatal("root has parent")
+		}
+		checkAvl(t, a.root.node)
+	}
+	if a.Len() != len(m) {
+		t.Fatalf("len:%d, want:%d", a.Len(), len(m))
+	}
+
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	i := 0
+	a.Range(func(k, v int) bool {
+		if k != keys[i] || v != m[k] {
+			t.Fatalf("index %d: got (%d, %d), want (%d, %d)", i, k, v, keys[i], m[keys[i]])
+		}
+		i++
+		return true
+	})
+}
+
+func randTree(r *rand.Rand, n, max int) (*AvlTree[int, int], map[int]int) {
+	a := New[int, int]()
+	m := make(map[int]int)
+	for i := 0; i < n; i++ {
+		k, v := r.Intn(max), r.Int()
+		a.Set(k, v)
+		m[k] = v
+	}
+	return a, m
+}
+
+func Test_AvlTree_Size(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a, m := randTree(r, 1000, 2000)
+	for i := 0; i < 1000; i++ {
+		k := r.Intn(2000)
+		a.Delete(k)
+		delete(m, k)
+	}
+	checkTreeMap(t, a, m)
+	if len(m) != 0 && a.root.node.size != len(m) {
+		t.Fatalf("root size:%d, want:%d", a.root.node.size, len(m))
+	}
+}
+
+func Test_AvlTree_Split(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		a, m := randTree(r, r.Intn(300), 500)
+		k := r.Intn(500)
+
+		left, right, found, ok := a.Split(k)
+		lm, rm := make(map[int]int), make(map[int]int)
+		for key, v := range m {
+			if key < k {
+				lm[key] = v
+			} else if key > k {
+				rm[key] = v
+			}
+		}
+
+		v, exist := m[k]
+		if ok != exist || found != v {
+			t.Fatalf("split %d: found (%d, %t), want (%d, %t)", k, found, ok, v, exist)
+		}
+		checkTreeMap(t, left, lm)
+		checkTreeMap(t, right, rm)
+		if a.Len() != 0 {
+			t.Fatal("a should be empty after Split")
+		}
+
+		// 再合并回去
+		if exist {
+			left.Set(k, v)
+		}
+		j, err := Join(left, right)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTreeMap(t, j, m)
+
+		// 合并之后的树可以继续修改
+		for n := 0; n < 50; n++ {
+			k := r.Intn(500)
+			if n%2 == 0 {
+				j.Set(k, n)
+				m[k] = n
+			} else {
+				j.Delete(k)
+				delete(m, k)
+			}
+		}
+		checkTreeMap(t, j, m)
+	}
+}
+
+func Test_AvlTree_Join_Overlap(t *testing.T) {
+	a, _ := FromSorted([]int{1, 2, 3}, []int{1, 2, 3})
+	b, _ := FromSorted([]int{3, 4}, []int{3, 4})
+	if _, err := Join(a, b); err != ErrOverlap {
+		t.Fatalf("got %v", err)
+	}
+
+	// 高度差很多的两棵树
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i + 10
+	}
+	big, _ := FromSorted(keys, keys)
+	small, _ := FromSorted([]int{1, 2}, []int{1, 2})
+	j, err := Join(small, big)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[int]int{1: 1, 2: 2}
+	for _, k := range keys {
+		m[k] = k
+	}
+	checkTreeMap(t, j, m)
+}
+
+func Test_AvlTree_SetOps(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		an, bn := r.Intn(300), r.Intn(300)
+		if i%4 == 0 {
+			bn = r.Intn(5)
+		}
+
+		for op := 0; op < 3; op++ {
+			a, am := randTree(r, an, 600)
+			b, bm := randTree(r, bn, 600)
+
+			want := make(map[int]int)
+			var got *AvlTree[int, int]
+			switch op {
+			case 0:
+				for k, v := range bm {
+					want[k] = v
+				}
+				for k, v := range am {
+					want[k] = v
+				}
+				got = Union(a, b)
+			case 1:
+				for k, v := range am {
+					if _, ok := bm[k]; ok {
+						want[k] = v
+					}
+				}
+				got = Intersection(a, b)
+			case 2:
+				for k, v := range am {
+					if _, ok := bm[k]; !ok {
+						want[k] = v
+					}
+				}
+				got = Difference(a, b)
+			}
+
+			checkTreeMap(t, got, want)
+			if a.Len() != 0 || b.Len() != 0 {
+				t.Fatal("a and b should be empty")
+			}
+		}
+	}
+}
+
+// DeleteRange用split/join实现, 端点存在和不存在, lo == hi, lo > hi都要覆盖
+func Test_AvlTree_DeleteRange(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 500; i++ {
+		a, m := randTree(r, r.Intn(200), 300)
+		lo, hi := r.Intn(320)-10, r.Intn(320)-10
+		if i%10 == 0 {
+			hi = lo
+		}
+		bd := []api.Bound{api.ClosedOpen, api.Closed, api.OpenClosed, api.Open}[i%4]
+
+		want := 0
+		for k := range m {
+			if (k > lo || k == lo && bd.IncludeLo()) && (k < hi || k == hi && bd.IncludeHi()) {
+				delete(m, k)
+				want++
+			}
+		}
+
+		if n := a.DeleteRange(lo, hi, bd); n != want {
+			t.Fatalf("DeleteRange(%d, %d, %d) = %d, want %d", lo, hi, bd, n, want)
+		}
+		checkTreeMap(t, a, m)
+	}
+}
+
+// 集合运算会消耗两个参数, 之后a和b都是空树, 再修改它们不会影响结果
+func Test_AvlTree_SetOps_Consume(t *testing.T) {
+	ops := []func(a, b *AvlTree[int, int]) *AvlTree[int, int]{
+		Union[int, int], Intersection[int, int], Difference[int, int],
+	}
+	for i, op := range ops {
+		a, b := New[int, int](), New[int, int]()
+		for k := 0; k < 10; k++ {
+			a.Set(k, k)
+			b.Set(k+5, k+5)
+		}
+
+		got := op(a, b)
+		want := got.Len()
+		for _, tr := range []*AvlTree[int, int]{a, b} {
+			if tr.Len() != 0 {
+				t.Fatalf("op %d: input len %d after consume", i, tr.Len())
+			}
+			tr.Range(func(k, v int) bool {
+				t.Fatalf("op %d: input still has key %d", i, k)
+				return false
+			})
+		}
+
+		a.Set(100, 100)
+		b.Set(101, 101)
+		if got.Len() != want || got.Get(100) != 0 || got.Get(101) != 0 {
+			t.Fatalf("op %d: result changed by writes to consumed inputs", i)
+		}
+	}
+}
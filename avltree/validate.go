@@ -0,0 +1,67 @@
+package avltree
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+
+	"github.com/antlabs/gstl/cmp"
+	"golang.org/x/exp/constraints"
+)
+
+type validator[K constraints.Ordered, V any] struct {
+	count int
+	prev  K
+}
+
+// 中序检查子树, 返回高度
+func (v *validator[K, V]) walk(n, parent *node[K, V]) (int, error) {
+	if n == nil {
+		return 0, nil
+	}
+
+	if n.parent != parent {
+		return 0, fmt.Errorf("avltree: key %v has wrong parent", n.key)
+	}
+
+	lh, err := v.walk(n.left, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if v.count > 0 && n.key <= v.prev {
+		return 0, fmt.Errorf("avltree: key %v after %v is out of order", n.key, v.prev)
+	}
+	v.prev = n.key
+	v.count++
+
+	rh, err := v.walk(n.right, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if diff := lh - rh; diff > 1 || diff < -1 {
+		return 0, fmt.Errorf("avltree: key %v has balance factor %d", n.key, diff)
+	}
+
+	h := cmp.Max(lh, rh) + 1
+	if n.height != h {
+		return 0, fmt.Errorf("avltree: key %v has height %d, want %d", n.key, n.height, h)
+	}
+	if n.size != n.leftSize()+n.rightSize()+1 {
+		return 0, fmt.Errorf("avltree: key %v has size %d, want %d", n.key, n.size, n.leftSize()+n.rightSize()+1)
+	}
+	return h, nil
+}
+
+// 检查高度, 平衡因子, key的顺序, parent指针和Len(), 有问题返回错误
+func (a *AvlTree[K, V]) Validate() error {
+	v := &validator[K, V]{}
+	if _, err := v.walk(a.root.node, nil); err != nil {
+		return err
+	}
+
+	if v.count != a.length {
+		return fmt.Errorf("avltree: Len() is %d, but has %d nodes", a.length, v.count)
+	}
+	return nil
+}
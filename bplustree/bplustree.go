@@ -0,0 +1,386 @@
+package bplustree
+
+// apache 2.0 antlabs
+
+// 参考资料
+// https://en.wikipedia.org/wiki/B%2B_tree
+// 和btree的区别
+// 1. 内部节点只存放索引key, 元素只放在叶子节点
+// 2. 叶子节点之间用双向链表连起来, 遍历的时候不需要在层之间来回递归
+import (
+	"sort"
+
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*BPlusTree[int, int])(nil)
+
+// b+树的节点
+// 内部节点: children[i]里面的key都在[keys[i-1], keys[i])区间, len(children) == len(keys)+1
+// 叶子节点: keys和vals一一对应
+type node[K constraints.Ordered, V any] struct {
+	keys     []K
+	vals     []V
+	children []*node[K, V]
+	prev     *node[K, V] // 只有叶子节点才有
+	next     *node[K, V] // 只有叶子节点才有
+}
+
+func (n *node[K, V]) leaf() bool {
+	return n.children == nil
+}
+
+// 内部节点里, 返回k所在孩子的索引
+func (n *node[K, V]) childIndex(k K) int {
+	return sort.Search(len(n.keys), func(i int) bool { return k < n.keys[i] })
+}
+
+// 叶子节点里, 返回第一个大于等于k的位置
+func (n *node[K, V]) find(k K) (index int, found bool) {
+	index = sort.Search(len(n.keys), func(i int) bool { return k <= n.keys[i] })
+	return index, index < len(n.keys) && n.keys[index] == k
+}
+
+// b+树头结点
+type BPlusTree[K constraints.Ordered, V any] struct {
+	count    int         //当前元素个数
+	root     *node[K, V] // root结点指针
+	head     *node[K, V] // 最左边的叶子
+	tail     *node[K, V] // 最右边的叶子
+	maxItems int
+	minItems int
+}
+
+// degree和btree.New的含义一样, 每个节点最多degree*2-1个key
+func New[K constraints.Ordered, V any](degree int) *BPlusTree[K, V] {
+	if degree <= 1 {
+		degree = 128
+	}
+
+	maxItems := degree*2 - 1
+	return &BPlusTree[K, V]{
+		maxItems: maxItems,
+		minItems: maxItems / 2,
+	}
+}
+
+// 返回元素的个数
+func (b *BPlusTree[K, V]) Len() int {
+	return b.count
+}
+
+// 获取值, 忽略找不到的情况
+func (b *BPlusTree[K, V]) Get(k K) (v V) {
+	v, _ = b.TryGet(k)
+	return
+}
+
+// 返回k所在的叶子节点
+func (b *BPlusTree[K, V]) findLeaf(k K) *node[K, V] {
+	n := b.root
+	if n == nil {
+		return nil
+	}
+
+	for !n.leaf() {
+		n = n.children[n.childIndex(k)]
+	}
+	return n
+}
+
+// 找到ok为true
+func (b *BPlusTree[K, V]) TryGet(k K) (v V, ok bool) {
+	n := b.findLeaf(k)
+	if n == nil {
+		return
+	}
+
+	i, found := n.find(k)
+	if !found {
+		return
+	}
+	return n.vals[i], true
+}
+
+// 设置接口, 有值就替换, 没有就新加
+func (b *BPlusTree[K, V]) Set(k K, v V) {
+	_, _ = b.Swap(k, v)
+}
+
+// 设置接口, 如果有值, 把prev值带返回, 并且被替换, 没有就新加
+func (b *BPlusTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	if b.root == nil {
+		b.root = &node[K, V]{keys: []K{k}, vals: []V{v}}
+		b.head, b.tail = b.root, b.root
+		b.count = 1
+		return
+	}
+
+	prev, replaced, right, median := b.insert(b.root, k, v)
+	if right != nil {
+		// root分裂, 树长高一层
+		b.root = &node[K, V]{
+			keys:     []K{median},
+			children: []*node[K, V]{b.root, right},
+		}
+	}
+
+	if !replaced {
+		b.count++
+	}
+	return
+}
+
+// 插入之后如果节点超过maxItems就分裂, 返回分裂出来的右节点和它的索引key
+func (b *BPlusTree[K, V]) insert(n *node[K, V], k K, v V) (prev V, replaced bool, right *node[K, V], median K) {
+	if n.leaf() {
+		i, found := n.find(k)
+		if found {
+			prev, n.vals[i] = n.vals[i], v
+			return prev, true, nil, median
+		}
+
+		n.keys = insertAt(n.keys, i, k)
+		n.vals = insertAt(n.vals, i, v)
+		if len(n.keys) > b.maxItems {
+			right, median = b.splitLeaf(n)
+		}
+		return
+	}
+
+	i := n.childIndex(k)
+	prev, replaced, child, childMedian := b.insert(n.children[i], k, v)
+	if child == nil {
+		return
+	}
+
+	n.keys = insertAt(n.keys, i, childMedian)
+	n.children = insertAt(n.children, i+1, child)
+	if len(n.keys) > b.maxItems {
+		right, median = b.splitInner(n)
+	}
+	return
+}
+
+// 叶子节点分裂, 右边第一个key复制一份到父节点
+func (b *BPlusTree[K, V]) splitLeaf(n *node[K, V]) (right *node[K, V], median K) {
+	mid := len(n.keys) / 2
+	right = &node[K, V]{
+		keys: append([]K(nil), n.keys[mid:]...),
+		vals: append([]V(nil), n.vals[mid:]...),
+	}
+	clear(n.vals[mid:])
+	n.keys = n.keys[:mid]
+	n.vals = n.vals[:mid]
+
+	// 把right挂到叶子链表里
+	right.prev = n
+	right.next = n.next
+	if n.next != nil {
+		n.next.prev = right
+	} else {
+		b.tail = right
+	}
+	n.next = right
+	return right, right.keys[0]
+}
+
+// 内部节点分裂, 中间的key移到父节点
+func (b *BPlusTree[K, V]) splitInner(n *node[K, V]) (right *node[K, V], median K) {
+	mid := len(n.keys) / 2
+	median = n.keys[mid]
+	right = &node[K, V]{
+		keys:     append([]K(nil), n.keys[mid+1:]...),
+		children: append([]*node[K, V](nil), n.children[mid+1:]...),
+	}
+	clear(n.children[mid+1:])
+	n.keys = n.keys[:mid]
+	n.children = n.children[:mid+1]
+	return
+}
+
+// 删除接口
+func (b *BPlusTree[K, V]) Delete(k K) {
+	b.DeleteWithPrev(k)
+}
+
+// 删除接口, 返回旧值
+func (b *BPlusTree[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
+	if b.root == nil {
+		return
+	}
+
+	prev, deleted = b.delete(b.root, k)
+	if !deleted {
+		return
+	}
+
+	b.count--
+	if b.count == 0 {
+		b.root, b.head, b.tail = nil, nil, nil
+		return
+	}
+
+	// root只剩一个孩子, 树变矮一层
+	if !b.root.leaf() && len(b.root.keys) == 0 {
+		b.root = b.root.children[0]
+	}
+	return
+}
+
+func (b *BPlusTree[K, V]) delete(n *node[K, V], k K) (prev V, deleted bool) {
+	if n.leaf() {
+		i, found := n.find(k)
+		if !found {
+			return
+		}
+
+		prev = n.vals[i]
+		n.keys = removeAt(n.keys, i)
+		n.vals = removeAt(n.vals, i)
+		return prev, true
+	}
+
+	// 内部节点的key只是索引, 被删除的key还留在内部节点里也不影响查找
+	i := n.childIndex(k)
+	prev, deleted = b.delete(n.children[i], k)
+	if deleted && len(n.children[i].keys) < b.minItems {
+		b.rebalance(n, i)
+	}
+	return
+}
+
+// 孩子节点i的元素太少, 和兄弟节点合并或者借一个元素
+func (b *BPlusTree[K, V]) rebalance(n *node[K, V], i int) {
+	if i == len(n.keys) {
+		i--
+	}
+
+	left, right := n.children[i], n.children[i+1]
+	if left.leaf() {
+		switch {
+		case len(left.keys)+len(right.keys) <= b.maxItems:
+			// 右叶子合并到左叶子
+			left.keys = append(left.keys, right.keys...)
+			left.vals = append(left.vals, right.vals...)
+			left.next = right.next
+			if right.next != nil {
+				right.next.prev = left
+			} else {
+				b.tail = left
+			}
+			n.keys = removeAt(n.keys, i)
+			n.children = removeAt(n.children, i+1)
+		case len(left.keys) > len(right.keys):
+			// 左叶子最后一个元素移到右叶子
+			last := len(left.keys) - 1
+			right.keys = insertAt(right.keys, 0, left.keys[last])
+			right.vals = insertAt(right.vals, 0, left.vals[last])
+			left.keys = removeAt(left.keys, last)
+			left.vals = removeAt(left.vals, last)
+			n.keys[i] = right.keys[0]
+		default:
+			// 右叶子第一个元素移到左叶子
+			left.keys = append(left.keys, right.keys[0])
+			left.vals = append(left.vals, right.vals[0])
+			right.keys = removeAt(right.keys, 0)
+			right.vals = removeAt(right.vals, 0)
+			n.keys[i] = right.keys[0]
+		}
+		return
+	}
+
+	switch {
+	case len(left.keys)+len(right.keys) < b.maxItems:
+		// 左=左+父+右
+		left.keys = append(append(left.keys, n.keys[i]), right.keys...)
+		left.children = append(left.children, right.children...)
+		n.keys = removeAt(n.keys, i)
+		n.children = removeAt(n.children, i+1)
+	case len(left.keys) > len(right.keys):
+		// 父到右, 左边最后一个当父
+		last := len(left.keys) - 1
+		right.keys = insertAt(right.keys, 0, n.keys[i])
+		right.children = insertAt(right.children, 0, left.children[last+1])
+		n.keys[i] = left.keys[last]
+		left.keys = removeAt(left.keys, last)
+		left.children = removeAt(left.children, last+1)
+	default:
+		// 父到左, 右边第一个当父
+		left.keys = append(left.keys, n.keys[i])
+		left.children = append(left.children, right.children[0])
+		n.keys[i] = right.keys[0]
+		right.keys = removeAt(right.keys, 0)
+		right.children = removeAt(right.children, 0)
+	}
+}
+
+// 升序遍历, 沿着叶子链表走
+func (b *BPlusTree[K, V]) Range(callback func(k K, v V) bool) {
+	for n := b.head; n != nil; n = n.next {
+		for i, k := range n.keys {
+			if !callback(k, n.vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// 降序遍历
+func (b *BPlusTree[K, V]) RangePrev(callback func(k K, v V) bool) {
+	for n := b.tail; n != nil; n = n.prev {
+		for i := len(n.keys) - 1; i >= 0; i-- {
+			if !callback(n.keys[i], n.vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// 返回最小的n个值, 升序返回, 比如0,1,2,3
+func (b *BPlusTree[K, V]) TopMin(limit int, callback func(k K, v V) bool) {
+	b.Range(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+// 返回最大的n个值, 降序返回, 10, 9, 8, 7
+func (b *BPlusTree[K, V]) TopMax(limit int, callback func(k K, v V) bool) {
+	b.RangePrev(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+func insertAt[T any](s []T, i int, e T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = e
+	return s
+}
+
+func removeAt[T any](s []T, i int) []T {
+	copy(s[i:], s[i+1:])
+	var zero T
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
@@ -0,0 +1,206 @@
+package bplustree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// 测试get set, 分裂逻辑
+func Test_BPlusTree_SetAndGet(t *testing.T) {
+	for _, degree := range []int{0, 2, 3, 16} {
+		b := New[int, int](degree)
+		max := 10000
+		for i := 0; i < max; i++ {
+			b.Set(i, i)
+		}
+
+		for i := 0; i < max; i++ {
+			v, ok := b.TryGet(i)
+			if !ok {
+				t.Errorf("Expected true, got false for key %d", i)
+			}
+			if v != i {
+				t.Errorf("Expected %d, got %d for key %d", i, v, i)
+			}
+		}
+
+		if b.Len() != max {
+			t.Errorf("Expected %d, got %d", max, b.Len())
+		}
+	}
+}
+
+// 替换
+func Test_BPlusTree_Swap(t *testing.T) {
+	b := New[int, int](2)
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+
+	for i := 0; i < 100; i++ {
+		prev, replaced := b.Swap(i, i*10)
+		if !replaced || prev != i {
+			t.Errorf("Expected %d true, got %d %t", i, prev, replaced)
+		}
+	}
+
+	if b.Len() != 100 {
+		t.Errorf("Expected 100, got %d", b.Len())
+	}
+}
+
+// 检查节点的key有序, 元素个数满足要求, 叶子链表完整
+func checkTree(t *testing.T, b *BPlusTree[int, int]) {
+	var leaves []*node[int, int]
+	var walk func(n *node[int, int], lo, hi *int, isRoot bool) int
+	walk = func(n *node[int, int], lo, hi *int, isRoot bool) int {
+		if len(n.keys) > b.maxItems || !isRoot && len(n.keys) < b.minItems {
+			t.Fatalf("bad node size %d", len(n.keys))
+		}
+
+		for i, k := range n.keys {
+			if i > 0 && n.keys[i-1] >= k || lo != nil && k < *lo || hi != nil && k >= *hi {
+				t.Fatalf("bad key order %v", n.keys)
+			}
+		}
+
+		if n.leaf() {
+			leaves = append(leaves, n)
+			return 1
+		}
+
+		if len(n.children) != len(n.keys)+1 {
+			t.Fatalf("Expected %d children, got %d", len(n.keys)+1, len(n.children))
+		}
+
+		depth := -1
+		for i, c := range n.children {
+			clo, chi := lo, hi
+			if i > 0 {
+				clo = &n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				chi = &n.keys[i]
+			}
+
+			d := walk(c, clo, chi, false)
+			if depth != -1 && d != depth {
+				t.Fatalf("leaves not at the same depth")
+			}
+			depth = d
+		}
+		return depth + 1
+	}
+
+	if b.root == nil {
+		if b.head != nil || b.tail != nil {
+			t.Fatalf("empty tree has leaves")
+		}
+		return
+	}
+
+	walk(b.root, nil, nil, true)
+	if b.head != leaves[0] || b.tail != leaves[len(leaves)-1] {
+		t.Fatalf("bad head or tail")
+	}
+	for i, n := range leaves {
+		if i > 0 && n.prev != leaves[i-1] || i+1 < len(leaves) && n.next != leaves[i+1] {
+			t.Fatalf("bad leaf link")
+		}
+	}
+}
+
+// 随机插入删除, 和map对比
+func Test_BPlusTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, degree := range []int{2, 3, 5} {
+		b := New[int, int](degree)
+		m := map[int]int{}
+		for i := 0; i < 20000; i++ {
+			k := r.Intn(2000)
+			if r.Intn(2) == 0 {
+				prev, deleted := b.DeleteWithPrev(k)
+				v, ok := m[k]
+				if deleted != ok || prev != v {
+					t.Fatalf("Delete %d Expected %d %t, got %d %t", k, v, ok, prev, deleted)
+				}
+				delete(m, k)
+			} else {
+				b.Set(k, i)
+				m[k] = i
+			}
+
+			if i%1000 == 0 {
+				checkTree(t, b)
+			}
+		}
+		checkTree(t, b)
+
+		keys := make([]int, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		var got []int
+		b.Range(func(k, v int) bool {
+			if m[k] != v {
+				t.Fatalf("key:%d Expected %d, got %d", k, m[k], v)
+			}
+			got = append(got, k)
+			return true
+		})
+		if !slicesEqual(got, keys) {
+			t.Fatalf("Range not equal")
+		}
+
+		// 全部删除
+		for _, k := range keys {
+			b.Delete(k)
+		}
+		checkTree(t, b)
+		if b.Len() != 0 {
+			t.Errorf("Expected 0, got %d", b.Len())
+		}
+	}
+}
+
+// TopMin和TopMax
+func Test_BPlusTree_Top(t *testing.T) {
+	b := New[int, int](2)
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+
+	var got []int
+	b.TopMin(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slicesEqual(got, []int{0, 1, 2}) {
+		t.Errorf("Expected [0 1 2], got %v", got)
+	}
+
+	got = got[:0]
+	b.TopMax(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slicesEqual(got, []int{99, 98, 97}) {
+		t.Errorf("Expected [99 98 97], got %v", got)
+	}
+}
+
+// Helper function to compare slices
+func slicesEqual[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
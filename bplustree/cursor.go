@@ -0,0 +1,112 @@
+package bplustree
+
+// apache 2.0 antlabs
+import "golang.org/x/exp/constraints"
+
+// 游标, 可以暂停, 恢复, 也可以从任意key开始双向遍历
+// 叶子之间有链表, 所以Next和Prev均摊O(1)
+// 除了Cursor.Delete, 其他对树的修改都会让游标失效, 需要重新Seek
+type Cursor[K constraints.Ordered, V any] struct {
+	b     *BPlusTree[K, V]
+	n     *node[K, V]
+	index int
+}
+
+// 返回一个未定位的游标, 使用前需要先调用Seek, SeekFirst或者SeekLast
+func (b *BPlusTree[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{b: b}
+}
+
+// 定位到第一个大于等于k的元素
+func (c *Cursor[K, V]) Seek(k K) bool {
+	c.n = c.b.findLeaf(k)
+	if c.n == nil {
+		return false
+	}
+
+	c.index, _ = c.n.find(k)
+	// 这个叶子里的元素都比k小, 下一个叶子的第一个元素就是要找的
+	if c.index == len(c.n.keys) {
+		c.n, c.index = c.n.next, 0
+	}
+	return c.n != nil
+}
+
+// 定位到最小的元素
+func (c *Cursor[K, V]) SeekFirst() bool {
+	c.n, c.index = c.b.head, 0
+	return c.n != nil
+}
+
+// 定位到最大的元素
+func (c *Cursor[K, V]) SeekLast() bool {
+	c.n = c.b.tail
+	if c.n == nil {
+		return false
+	}
+	c.index = len(c.n.keys) - 1
+	return true
+}
+
+// 移到下一个元素, 没有下一个元素时游标失效
+func (c *Cursor[K, V]) Next() bool {
+	if c.n == nil {
+		return false
+	}
+
+	c.index++
+	if c.index == len(c.n.keys) {
+		c.n, c.index = c.n.next, 0
+	}
+	return c.n != nil
+}
+
+// 移到上一个元素, 没有上一个元素时游标失效
+func (c *Cursor[K, V]) Prev() bool {
+	if c.n == nil {
+		return false
+	}
+
+	c.index--
+	if c.index < 0 {
+		c.n = c.n.prev
+		if c.n == nil {
+			return false
+		}
+		c.index = len(c.n.keys) - 1
+	}
+	return true
+}
+
+// 游标是否指向一个元素
+func (c *Cursor[K, V]) Valid() bool {
+	return c.n != nil
+}
+
+// 当前元素的key, 游标失效时返回零值
+func (c *Cursor[K, V]) Key() (k K) {
+	if c.n == nil {
+		return
+	}
+	return c.n.keys[c.index]
+}
+
+// 当前元素的value, 游标失效时返回零值
+func (c *Cursor[K, V]) Value() (v V) {
+	if c.n == nil {
+		return
+	}
+	return c.n.vals[c.index]
+}
+
+// 删除当前元素, 游标移到下一个元素
+// 删除可能合并叶子, 所以删除之后按原来的key重新Seek
+func (c *Cursor[K, V]) Delete() {
+	if c.n == nil {
+		return
+	}
+
+	k := c.Key()
+	c.b.Delete(k)
+	c.Seek(k)
+}
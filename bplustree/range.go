@@ -0,0 +1,25 @@
+package bplustree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+)
+
+// 升序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+// 直接从lo开始, 不需要从头遍历
+func (b *BPlusTree[K, V]) RangeBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.Between[K, V](b.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 降序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+func (b *BPlusTree[K, V]) RangePrevBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.PrevBetween[K, V](b.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 删除[lo, hi)区间的所有元素, 返回删除的个数, 可以通过bound修改区间的开闭
+// 不是批量删除: 每个元素都是一次Delete加一次重新Seek, 复杂度是O(k*log(n)), k是删除的个数
+// 不会整个叶子一起摘掉, 经常要删除大区间可以改用avltree
+func (b *BPlusTree[K, V]) DeleteRange(lo, hi K, bound ...api.Bound) (n int) {
+	return rangeop.Delete[K, V](b.Cursor(), lo, hi, rangeop.GetBound(bound))
+}
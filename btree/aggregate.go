@@ -0,0 +1,116 @@
+package btree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+	"golang.org/x/exp/constraints"
+)
+
+// 幺半群, Combine要满足结合律, Identity是单位元
+// 比如求和: Combine是a+b, Identity是0
+// 聚合的时候按key的顺序调用Combine, 不要求满足交换律
+type Monoid[V any] interface {
+	Combine(a, b V) V
+	Identity() V
+}
+
+// 每个节点维护子树value聚合值的btree, Aggregate是O(log n)
+// 除了下面的方法, Btree的方法都可以直接使用
+type AggTree[K constraints.Ordered, V any] struct {
+	Btree[K, V]
+}
+
+// degree和New的含义一样
+func NewAgg[K constraints.Ordered, V any](degree int, m Monoid[V]) *AggTree[K, V] {
+	a := &AggTree[K, V]{Btree: *New[K, V](degree)}
+	a.monoid = m
+	return a
+}
+
+// 复制一棵树, O(1)
+func (a *AggTree[K, V]) Clone() *AggTree[K, V] {
+	return &AggTree[K, V]{Btree: *a.Btree.Clone()}
+}
+
+// 按key的顺序重新计算节点的聚合值, 孩子节点的聚合值必须已经是对的
+func (b *Btree[K, V]) aggUpdate(n *node[K, V]) {
+	if b.monoid == nil {
+		return
+	}
+
+	acc := b.monoid.Identity()
+	leaf := n.leaf()
+	items := n.items.Len()
+	for i := 0; i < items; i++ {
+		if !leaf {
+			acc = b.monoid.Combine(acc, n.children.Get(i).agg)
+		}
+		acc = b.monoid.Combine(acc, n.items.Get(i).val)
+	}
+	if !leaf {
+		acc = b.monoid.Combine(acc, n.children.Get(items).agg)
+	}
+	n.agg = acc
+}
+
+// 计算子树里面在区间内的聚合值
+// hasLo, hasHi为false表示这一边不用检查, 两边都不用检查时直接用节点的聚合值
+func (b *Btree[K, V]) aggregate(n *node[K, V], lo, hi K, bd api.Bound, hasLo, hasHi bool) V {
+	if !hasLo && !hasHi {
+		return n.agg
+	}
+
+	m := b.monoid
+	acc := m.Identity()
+	leaf := n.leaf()
+	items := n.items.Len()
+	for i := 0; i <= items; i++ {
+		// 第i个孩子的key都在items[i-1]和items[i]之间
+		if !leaf {
+			childLo, childHi := hasLo, hasHi
+			if i > 0 && n.items.Get(i-1).key >= lo {
+				childLo = false
+			}
+			if i < items && n.items.Get(i).key <= hi {
+				childHi = false
+			}
+
+			disjoint := hasLo && i < items && n.items.Get(i).key <= lo ||
+				hasHi && i > 0 && n.items.Get(i-1).key >= hi
+			if !disjoint {
+				acc = m.Combine(acc, b.aggregate(n.children.Get(i), lo, hi, bd, childLo, childHi))
+			}
+		}
+
+		if i == items {
+			break
+		}
+
+		item := n.items.Get(i)
+		if hasLo && (item.key < lo || item.key == lo && !bd.IncludeLo()) {
+			continue
+		}
+		if hasHi && (item.key > hi || item.key == hi && !bd.IncludeHi()) {
+			break
+		}
+		acc = m.Combine(acc, item.val)
+	}
+	return acc
+}
+
+// 返回[lo, hi)区间内所有value的聚合值, 可以通过bound修改区间的开闭, O(log n)
+func (a *AggTree[K, V]) Aggregate(lo, hi K, bound ...api.Bound) V {
+	if a.root == nil || lo > hi {
+		return a.monoid.Identity()
+	}
+	return a.aggregate(a.root, lo, hi, rangeop.GetBound(bound), true, true)
+}
+
+// 返回所有value的聚合值, O(1)
+func (a *AggTree[K, V]) AggregateAll() V {
+	if a.root == nil {
+		return a.monoid.Identity()
+	}
+	return a.root.agg
+}
@@ -0,0 +1,135 @@
+package btree
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+)
+
+type sumMonoid struct{}
+
+func (sumMonoid) Combine(a, b int) int { return a + b }
+func (sumMonoid) Identity() int        { return 0 }
+
+// 字符串拼接不满足交换律, 可以检查Combine的顺序
+type concatMonoid struct{}
+
+func (concatMonoid) Combine(a, b string) string { return a + b }
+func (concatMonoid) Identity() string           { return "" }
+
+// 检查每个节点的聚合值
+func checkAgg(t *testing.T, b *Btree[int, int], n *node[int, int]) int {
+	sum := 0
+	for i := 0; i < n.items.Len(); i++ {
+		sum += n.items.Get(i).val
+	}
+	if !n.leaf() {
+		for i := 0; i < n.children.Len(); i++ {
+			sum += checkAgg(t, b, n.children.Get(i))
+		}
+	}
+	if n.agg != sum {
+		t.Fatalf("node agg:%d, want:%d", n.agg, sum)
+	}
+	return sum
+}
+
+func Test_AggTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a := NewAgg[int, int](2, sumMonoid{})
+	m := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		switch r.Intn(10) {
+		case 0, 1, 2:
+			a.Delete(k)
+			delete(m, k)
+		case 3:
+			a.DeleteRange(k, k+5)
+			for d := k; d < k+5; d++ {
+				delete(m, d)
+			}
+		default:
+			v := r.Intn(100)
+			a.Set(k, v)
+			m[k] = v
+		}
+
+		if i%250 != 0 {
+			continue
+		}
+
+		if a.root != nil {
+			checkAgg(t, &a.Btree, a.root)
+		}
+		for j := 0; j < 50; j++ {
+			lo, hi := r.Intn(520)-10, r.Intn(520)-10
+			for _, bd := range []api.Bound{api.ClosedOpen, api.Closed, api.OpenClosed, api.Open} {
+				want := 0
+				for k, v := range m {
+					if (k > lo || k == lo && bd.IncludeLo()) && (k < hi || k == hi && bd.IncludeHi()) {
+						want += v
+					}
+				}
+
+				if got := a.Aggregate(lo, hi, bd); got != want {
+					t.Fatalf("Aggregate(%d, %d, %d):%d, want:%d", lo, hi, bd, got, want)
+				}
+			}
+		}
+	}
+
+	want := 0
+	for _, v := range m {
+		want += v
+	}
+	if a.AggregateAll() != want {
+		t.Fatalf("AggregateAll:%d, want:%d", a.AggregateAll(), want)
+	}
+}
+
+func Test_AggTree_Order(t *testing.T) {
+	a := NewAgg[int, string](2, concatMonoid{})
+	keys := rand.New(rand.NewSource(2)).Perm(100)
+	for _, k := range keys {
+		a.Set(k, strconv.Itoa(k)+",")
+	}
+
+	sort.Ints(keys)
+	want := ""
+	for _, k := range keys[10:50] {
+		want += strconv.Itoa(k) + ","
+	}
+	if got := a.Aggregate(10, 50); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got := a.Aggregate(50, 10); got != "" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func Test_AggTree_Clone(t *testing.T) {
+	a := NewAgg[int, int](2, sumMonoid{})
+	for i := 0; i < 100; i++ {
+		a.Set(i, i)
+	}
+
+	c := a.Clone()
+	for i := 0; i < 50; i++ {
+		c.Delete(i)
+	}
+	a.Set(1000, 1)
+
+	if got := a.AggregateAll(); got != 99*100/2+1 {
+		t.Fatalf("a:%d", got)
+	}
+	if got := c.AggregateAll(); got != (50+99)*50/2 {
+		t.Fatalf("c:%d", got)
+	}
+	checkAgg(t, &a.Btree, a.root)
+	checkAgg(t, &c.Btree, c.root)
+}
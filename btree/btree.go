@@ -4,6 +4,7 @@ package btree
 
 // 参考资料
 // https://github.com/tidwall/btree
+// https://github.com/google/btree
 import (
 	"fmt"
 
@@ -22,6 +23,14 @@ type Btree[K constraints.Ordered, V any] struct {
 	root     *node[K, V] // root结点指针
 	maxItems int
 	minItems int
+	cow      *copyOnWrite // 只能修改cow相同的节点, 其他节点要先复制
+	monoid   Monoid[V]    // 不为nil时每个节点维护子树的聚合值
+}
+
+// 写时复制的标记, Clone之后新老两棵树各自拿一个新的标记
+// 不能是空结构体, 空结构体的指针可能相同
+type copyOnWrite struct {
+	_ byte
 }
 
 // 元素
@@ -34,6 +43,8 @@ type pair[K constraints.Ordered, V any] struct {
 type node[K constraints.Ordered, V any] struct {
 	items    *vec.Vec[pair[K, V]]  //存放元素的节点
 	children *vec.Vec[*node[K, V]] //孩子节点
+	cow      *copyOnWrite          //创建这个节点的树的标记
+	agg      V                     //子树所有value的聚合值, 只有设置了monoid才有
 }
 
 func (n *node[K, V]) leaf() bool {
@@ -50,9 +61,42 @@ func New[K constraints.Ordered, V any](degree int) *Btree[K, V] {
 	return &Btree[K, V]{
 		maxItems: maxItems,
 		minItems: maxItems / 2,
+		cow:      new(copyOnWrite),
 	}
 }
 
+// 复制一棵树, O(1)
+// 两棵树共享所有的节点, 哪边修改节点, 哪边先复制这个节点, 所以两棵树互不影响
+func (b *Btree[K, V]) Clone() *Btree[K, V] {
+	clone := *b
+	b.cow = new(copyOnWrite)
+	clone.cow = new(copyOnWrite)
+	return &clone
+}
+
+// 返回可以修改的节点, 节点不属于b就复制一份
+func (b *Btree[K, V]) mutable(n *node[K, V]) *node[K, V] {
+	if n.cow == b.cow {
+		return n
+	}
+
+	c := &node[K, V]{cow: b.cow, agg: n.agg}
+	if n.items != nil {
+		c.items = n.items.Clone()
+	}
+	if n.children != nil {
+		c.children = n.children.Clone()
+	}
+	return c
+}
+
+// 返回可以修改的第i个孩子节点
+func (b *Btree[K, V]) mutableChild(n *node[K, V], i int) *node[K, V] {
+	c := b.mutable(n.children.Get(i))
+	n.children.Set(i, c)
+	return c
+}
+
 // 返回btree中元素的个数
 func (b *Btree[K, V]) Len() int {
 	return b.count
@@ -66,7 +110,7 @@ func (b *Btree[K, V]) Set(k K, v V) {
 
 // 新建一个节点
 func (b *Btree[K, V]) newNode(leaf bool) (n *node[K, V]) {
-	n = &node[K, V]{}
+	n = &node[K, V]{cow: b.cow}
 	if !leaf {
 		n.children = vec.New[*node[K, V]]()
 	}
@@ -108,6 +152,8 @@ func (b *Btree[K, V]) nodeSplit(n *node[K, V]) (right *node[K, V], median pair[K
 		right.children = n.children.SplitOff(i + 1)
 	}
 
+	b.aggUpdate(n)
+	b.aggUpdate(right)
 	return
 }
 
@@ -120,6 +166,7 @@ func (b *Btree[K, V]) nodeSet(n *node[K, V], item pair[K, V]) (prev V, replaced
 		prevPtr := n.items.GetPtr(i)
 		prev = prevPtr.val
 		prevPtr.val = item.val
+		b.aggUpdate(n)
 		return prev, true, false
 	}
 
@@ -131,10 +178,11 @@ func (b *Btree[K, V]) nodeSet(n *node[K, V], item pair[K, V]) (prev V, replaced
 			return
 		}
 		n.items.Insert(i, item)
+		b.aggUpdate(n)
 		return
 	}
 
-	prev, replaced, needSplit = b.nodeSet(n.children.Get(i), item)
+	prev, replaced, needSplit = b.nodeSet(b.mutableChild(n, i), item)
 	if needSplit {
 		// 没有位置插入新元素, 上层节点需要分裂
 		if n.items.Len() == b.maxItems {
@@ -151,6 +199,7 @@ func (b *Btree[K, V]) nodeSet(n *node[K, V], item pair[K, V]) (prev V, replaced
 		return b.nodeSet(n, item)
 	}
 
+	b.aggUpdate(n)
 	return
 }
 
@@ -164,10 +213,12 @@ func (b *Btree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
 			b.root.items = vec.New[pair[K, V]]()
 		}
 		b.root.items.Push(item)
+		b.aggUpdate(b.root)
 		b.count = 1
 		return
 	}
 
+	b.root = b.mutable(b.root)
 	prev, replaced, needSplit := b.nodeSet(b.root, item)
 	if needSplit {
 		left := b.root
@@ -234,6 +285,7 @@ func (b *Btree[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
 		return
 	}
 
+	b.root = b.mutable(b.root)
 	prevPair, deleted := b.delete(b.root, false, k)
 	if !deleted {
 		return
@@ -276,20 +328,21 @@ func (b *Btree[K, V]) delete(n *node[K, V], max bool, k K) (prev pair[K, V], del
 			// 叶子结点直接删除走人
 			prev = n.items.Get(i)
 			n.items.Remove(i)
+			b.aggUpdate(n)
 			return prev, true
 		}
 
 		if max {
 			i++
-			prev, deleted = b.delete(n.children.Get(i), true, emptykv.key)
+			prev, deleted = b.delete(b.mutableChild(n, i), true, emptykv.key)
 		} else {
 			prev = n.items.Get(i)
-			maxItems, _ := b.delete(n.children.Get(i), true, emptykv.key)
+			maxItems, _ := b.delete(b.mutableChild(n, i), true, emptykv.key)
 			deleted = true
 			n.items.Set(i, maxItems)
 		}
 	} else {
-		prev, deleted = b.delete(n.children.Get(i), max, k)
+		prev, deleted = b.delete(b.mutableChild(n, i), max, k)
 	}
 
 	if !deleted {
@@ -301,6 +354,7 @@ func (b *Btree[K, V]) delete(n *node[K, V], max bool, k K) (prev pair[K, V], del
 		b.rebalance(n, i)
 	}
 
+	b.aggUpdate(n)
 	return prev, true
 }
 
@@ -309,7 +363,7 @@ func (b *Btree[K, V]) rebalance(n *node[K, V], i int) {
 		i--
 	}
 
-	left, right := n.children.Get(i), n.children.Get(i+1)
+	left, right := b.mutableChild(n, i), b.mutableChild(n, i+1)
 
 	// 左右元素相加 < maxItems
 	if left.items.Len()+right.items.Len() < b.maxItems {
@@ -328,7 +382,11 @@ func (b *Btree[K, V]) rebalance(n *node[K, V], i int) {
 		n.items.Remove(i)
 		// 删除右叶子
 		n.children.Remove(i + 1)
-	} else if left.items.Len() > right.items.Len() {
+		b.aggUpdate(left)
+		return
+	}
+
+	if left.items.Len() > right.items.Len() {
 		// 向右移动
 		// 父到右
 		right.items.Insert(0, n.items.Get(i))
@@ -372,6 +430,9 @@ func (b *Btree[K, V]) rebalance(n *node[K, V], i int) {
 			left.children.Push(first)
 		}
 	}
+
+	b.aggUpdate(left)
+	b.aggUpdate(right)
 }
 
 // 遍历b tree
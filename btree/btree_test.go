@@ -1,6 +1,7 @@
 package btree
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/antlabs/gstl/cmp"
@@ -400,6 +401,87 @@ func Test_Btree_Delete2(t *testing.T) {
 	}
 }
 
+func btreeToMap(b *Btree[int, int]) map[int]int {
+	m := map[int]int{}
+	b.Range(func(k, v int) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
+
+func equalMap(a, b map[int]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if v2, ok := b[k]; !ok || v2 != v {
+			return false
+		}
+	}
+	return true
+}
+
+// clone之后, 修改任何一边都不影响另外一边
+func Test_Btree_Clone(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	b := New[int, int](2)
+	m := map[int]int{}
+	for i := 0; i < 1000; i++ {
+		b.Set(i, i)
+		m[i] = i
+	}
+
+	trees := []*Btree[int, int]{b}
+	models := []map[int]int{m}
+	for round := 0; round < 5; round++ {
+		// 随便挑一棵树clone
+		i := r.Intn(len(trees))
+		trees = append(trees, trees[i].Clone())
+		clone := map[int]int{}
+		for k, v := range models[i] {
+			clone[k] = v
+		}
+		models = append(models, clone)
+
+		// 每棵树各自随机修改
+		for j, tree := range trees {
+			for n := 0; n < 500; n++ {
+				k := r.Intn(1500)
+				if r.Intn(2) == 0 {
+					tree.Delete(k)
+					delete(models[j], k)
+				} else {
+					tree.Set(k, n)
+					models[j][k] = n
+				}
+			}
+		}
+
+		for j, tree := range trees {
+			if tree.Len() != len(models[j]) {
+				t.Fatalf("tree %d Expected %d, got %d", j, len(models[j]), tree.Len())
+			}
+			if !equalMap(btreeToMap(tree), models[j]) {
+				t.Fatalf("tree %d changed by other tree", j)
+			}
+		}
+	}
+}
+
+// clone一棵空树
+func Test_Btree_Clone_Empty(t *testing.T) {
+	b := New[int, int](2)
+	c := b.Clone()
+	c.Set(1, 1)
+	if b.Len() != 0 {
+		t.Errorf("Expected 0, got %d", b.Len())
+	}
+	if v := c.Get(1); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+}
+
 // Helper function to compare slices
 func slicesEqual[T comparable](a, b []T) bool {
 	if len(a) != len(b) {
@@ -0,0 +1,194 @@
+package btree
+
+// apache 2.0 antlabs
+import "golang.org/x/exp/constraints"
+
+// 游标走过的路径
+// 栈顶的index指向当前元素n.items[index]
+// 其他层的index表示从n.children[index]走了下去
+type cursorFrame[K constraints.Ordered, V any] struct {
+	n     *node[K, V]
+	index int
+}
+
+// 游标, 可以暂停, 恢复, 也可以从任意key开始双向遍历
+// btree的节点没有parent指针, 所以用栈保存从root到当前元素的路径
+// 除了Cursor.Delete, 其他对树的修改都会让游标失效, 需要重新Seek
+type Cursor[K constraints.Ordered, V any] struct {
+	b     *Btree[K, V]
+	stack []cursorFrame[K, V]
+}
+
+// 返回一个未定位的游标, 使用前需要先调用Seek, SeekFirst或者SeekLast
+func (b *Btree[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{b: b}
+}
+
+func (c *Cursor[K, V]) push(n *node[K, V], index int) {
+	c.stack = append(c.stack, cursorFrame[K, V]{n: n, index: index})
+}
+
+func (c *Cursor[K, V]) top() *cursorFrame[K, V] {
+	return &c.stack[len(c.stack)-1]
+}
+
+// 从n开始一直往左走, 停在最小的元素上
+func (c *Cursor[K, V]) pushLeft(n *node[K, V]) {
+	for !n.leaf() {
+		c.push(n, 0)
+		n = n.children.Get(0)
+	}
+	c.push(n, 0)
+}
+
+// 从n开始一直往右走, 停在最大的元素上
+func (c *Cursor[K, V]) pushRight(n *node[K, V]) {
+	for !n.leaf() {
+		c.push(n, n.items.Len())
+		n = n.children.Get(n.items.Len())
+	}
+	c.push(n, n.items.Len()-1)
+}
+
+// 子树已经走完, 往上找下一个元素
+func (c *Cursor[K, V]) popNext() bool {
+	for c.stack = c.stack[:len(c.stack)-1]; len(c.stack) > 0; c.stack = c.stack[:len(c.stack)-1] {
+		if f := c.top(); f.index < f.n.items.Len() {
+			return true
+		}
+	}
+	return false
+}
+
+// 子树已经走完, 往上找上一个元素
+func (c *Cursor[K, V]) popPrev() bool {
+	for c.stack = c.stack[:len(c.stack)-1]; len(c.stack) > 0; c.stack = c.stack[:len(c.stack)-1] {
+		if f := c.top(); f.index > 0 {
+			f.index--
+			return true
+		}
+	}
+	return false
+}
+
+// 定位到第一个大于等于k的元素
+func (c *Cursor[K, V]) Seek(k K) bool {
+	c.stack = c.stack[:0]
+	if c.b.root == nil {
+		return false
+	}
+
+	n := c.b.root
+	for {
+		i, found := c.b.find(n, k)
+		c.push(n, i)
+		if found {
+			return true
+		}
+
+		if n.leaf() {
+			if i < n.items.Len() {
+				return true
+			}
+			return c.popNext()
+		}
+
+		n = n.children.Get(i)
+	}
+}
+
+// 定位到最小的元素
+func (c *Cursor[K, V]) SeekFirst() bool {
+	c.stack = c.stack[:0]
+	if c.b.root == nil {
+		return false
+	}
+
+	c.pushLeft(c.b.root)
+	return true
+}
+
+// 定位到最大的元素
+func (c *Cursor[K, V]) SeekLast() bool {
+	c.stack = c.stack[:0]
+	if c.b.root == nil {
+		return false
+	}
+
+	c.pushRight(c.b.root)
+	return true
+}
+
+// 移到下一个元素, 没有下一个元素时游标失效
+func (c *Cursor[K, V]) Next() bool {
+	if !c.Valid() {
+		return false
+	}
+
+	f := c.top()
+	if !f.n.leaf() {
+		f.index++
+		c.pushLeft(f.n.children.Get(f.index))
+		return true
+	}
+
+	f.index++
+	if f.index < f.n.items.Len() {
+		return true
+	}
+	return c.popNext()
+}
+
+// 移到上一个元素, 没有上一个元素时游标失效
+func (c *Cursor[K, V]) Prev() bool {
+	if !c.Valid() {
+		return false
+	}
+
+	f := c.top()
+	if !f.n.leaf() {
+		c.pushRight(f.n.children.Get(f.index))
+		return true
+	}
+
+	if f.index > 0 {
+		f.index--
+		return true
+	}
+	return c.popPrev()
+}
+
+// 游标是否指向一个元素
+func (c *Cursor[K, V]) Valid() bool {
+	return len(c.stack) > 0
+}
+
+// 当前元素的key, 游标失效时返回零值
+func (c *Cursor[K, V]) Key() (k K) {
+	if !c.Valid() {
+		return
+	}
+	f := c.top()
+	return f.n.items.Get(f.index).key
+}
+
+// 当前元素的value, 游标失效时返回零值
+func (c *Cursor[K, V]) Value() (v V) {
+	if !c.Valid() {
+		return
+	}
+	f := c.top()
+	return f.n.items.Get(f.index).val
+}
+
+// 删除当前元素, 游标移到下一个元素
+// 删除会合并或者借用兄弟节点的元素, 路径会变, 所以删除之后按原来的key重新Seek
+func (c *Cursor[K, V]) Delete() {
+	if !c.Valid() {
+		return
+	}
+
+	k := c.Key()
+	c.b.Delete(k)
+	c.Seek(k)
+}
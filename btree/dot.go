@@ -0,0 +1,62 @@
+package btree
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// 输出graphviz dot格式, 每个节点是一个record, 图的标题是degree
+// opts为nil时使用默认的选项
+func (b *Btree[K, V]) WriteDOT(w io.Writer, opts *dot.Options[K, V]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph btree {")
+	dw.Printf("\tlabel=%s;", idot.Quote(fmt.Sprintf("degree=%d", (b.maxItems+1)/2)))
+	dw.Printf("\tnode [shape=record];")
+	if b.root != nil {
+		b.root.writeDOT(dw, 0)
+	}
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 返回节点名
+func (n *node[K, V]) writeDOT(w *idot.Writer[K, V], depth int) string {
+	if w.Truncated(depth) {
+		return w.Ellipsis()
+	}
+
+	id := w.NextID()
+	leaf := n.leaf()
+	items := 0
+	if n.items != nil {
+		items = n.items.Len()
+	}
+
+	// 内部节点的key之间留出孩子的位置 <c0>|k0|<c1>|k1|<c2>
+	var fields []string
+	for i := 0; i < items; i++ {
+		if !leaf {
+			fields = append(fields, fmt.Sprintf("<c%d>", i))
+		}
+		item := n.items.Get(i)
+		fields = append(fields, idot.EscapeRecord(w.Label(item.key, item.val)))
+	}
+	if !leaf {
+		fields = append(fields, fmt.Sprintf("<c%d>", items))
+	}
+	w.Printf("\t%s [label=\"%s\"];", id, strings.Join(fields, "|"))
+
+	if leaf {
+		return id
+	}
+
+	for i := 0; i < n.children.Len(); i++ {
+		w.Printf("\t%s:c%d -> %s;", id, i, n.children.Get(i).writeDOT(w, depth+1))
+	}
+	return id
+}
@@ -0,0 +1,32 @@
+package btree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_Btree_WriteDOT(t *testing.T) {
+	b := New[int, int](2)
+	for i := 0; i < 10; i++ {
+		b.Set(i, i)
+	}
+
+	var buf bytes.Buffer
+	if err := b.WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph btree {") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	if !strings.Contains(out, `label="degree=2"`) || !strings.Contains(out, ":c0 ->") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	for i := 0; i < 10; i++ {
+		if !strings.Contains(out, string(rune('0'+i))) {
+			t.Fatalf("missing key %d:\n%s", i, out)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package btree
+
+import (
+	"sort"
+	"testing"
+)
+
+// 每两个字节是一个操作, 第一个字节是操作类型, 第二个字节是key
+// 每次操作之后调用Validate, 最后和map对比
+func FuzzBtree(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 2, 2})
+	f.Add([]byte{0, 10, 1, 20, 0, 30, 1, 40, 3, 15, 2, 10, 0, 10})
+	f.Fuzz(func(t *testing.T, ops []byte) {
+		tr := New[int, int](2)
+		m := make(map[int]int)
+		for i := 0; i+1 < len(ops); i += 2 {
+			k := int(ops[i+1])
+			switch ops[i] % 4 {
+			case 0, 1:
+				tr.Set(k, i)
+				m[k] = i
+			case 2:
+				tr.Delete(k)
+				delete(m, k)
+			case 3:
+				// 删除[k, k+8)区间
+				tr.DeleteRange(k, k+8)
+				for d := k; d < k+8; d++ {
+					delete(m, d)
+				}
+			}
+
+			if err := tr.Validate(); err != nil {
+				t.Fatalf("op %d: %v", i/2, err)
+			}
+		}
+
+		keys := make([]int, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		if tr.Len() != len(keys) {
+			t.Fatalf("len:%d, want:%d", tr.Len(), len(keys))
+		}
+
+		i := 0
+		tr.Range(func(k, v int) bool {
+			if k != keys[i] || v != m[k] {
+				t.Fatalf("index %d: got (%d, %d), want (%d, %d)", i, k, v, keys[i], m[keys[i]])
+			}
+			i++
+			return true
+		})
+	})
+}
+
+func Test_Btree_Validate(t *testing.T) {
+	b := New[int, int](2)
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+	if err := b.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	b.count++
+	if b.Validate() == nil {
+		t.Fatal("wrong count should fail")
+	}
+	b.count--
+
+	b.root.items.Set(0, pair[int, int]{key: 1000})
+	if b.Validate() == nil {
+		t.Fatal("keys out of order should fail")
+	}
+}
@@ -0,0 +1,25 @@
+package btree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+)
+
+// 升序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+// 直接从lo开始, 不需要从头遍历
+func (b *Btree[K, V]) RangeBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.Between[K, V](b.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 降序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+func (b *Btree[K, V]) RangePrevBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.PrevBetween[K, V](b.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 删除[lo, hi)区间的所有元素, 返回删除的个数, 可以通过bound修改区间的开闭
+// 不是批量删除: 每个元素都是一次Delete加一次重新Seek, 各自合并节点, 复杂度是O(k*log(n)), k是删除的个数
+// 不会整棵子树一起摘掉, 删除大区间时不如用剩下的元素FromSorted重新建树, 或者改用avltree
+func (b *Btree[K, V]) DeleteRange(lo, hi K, bound ...api.Bound) (n int) {
+	return rangeop.Delete[K, V](b.Cursor(), lo, hi, rangeop.GetBound(bound))
+}
@@ -0,0 +1,109 @@
+package btree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/internal/bulk"
+	"github.com/antlabs/gstl/vec"
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	ErrNotSorted    = bulk.ErrNotSorted
+	ErrDuplicateKey = bulk.ErrDuplicateKey
+	ErrLenMismatch  = bulk.ErrLenMismatch
+)
+
+// 从升序的keys, vals构造btree, O(n), degree和New的含义一样
+// keys不是升序或者有重复的key会返回错误
+func FromSorted[K constraints.Ordered, V any](degree int, keys []K, vals []V) (*Btree[K, V], error) {
+	f, err := bulk.Slices(keys, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSortedFunc(degree, len(keys), f)
+}
+
+// 从升序的n个元素构造btree, O(n), f按0到n-1的顺序调用
+// key不是升序或者有重复的key会返回错误, 发现错误之后不会再调用f
+func FromSortedFunc[K constraints.Ordered, V any](degree int, n int, f func(i int) (K, V)) (*Btree[K, V], error) {
+	b := New[K, V](degree)
+	if n <= 0 {
+		return b, nil
+	}
+
+	// 找到能放下n个元素的最小高度
+	height := 1
+	for maxSubtree(b.maxItems+1, height, n) < n {
+		height++
+	}
+
+	it := bulk.NewIter(f)
+	b.root = b.buildSorted(it, n, height)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	b.count = n
+	return b, nil
+}
+
+func nextPair[K constraints.Ordered, V any](it *bulk.Iter[K, V]) (item pair[K, V]) {
+	item.key, item.val = it.Next()
+	return
+}
+
+// 高度为height的子树最多能放的元素个数, 也就是fanout^height-1
+// 超过limit就直接返回limit+1, 防止溢出
+func maxSubtree(fanout, height, limit int) int {
+	total := 1
+	for i := 0; i < height; i++ {
+		total *= fanout
+		if total > limit+1 {
+			return limit + 1
+		}
+	}
+	return total - 1
+}
+
+// 用接下来的m个元素构造高度为height的子树
+// 非root节点的子树至少有(minItems+1)^height-1个元素, 平均分给每个孩子, 每个节点的元素个数都在[minItems, maxItems]之间
+func (b *Btree[K, V]) buildSorted(it *bulk.Iter[K, V], m int, height int) *node[K, V] {
+	if height == 1 {
+		n := b.newLeaf()
+		n.items = vec.WithCapacity[pair[K, V]](m)
+		for i := 0; i < m && it.Err() == nil; i++ {
+			n.items.Push(nextPair(it))
+		}
+		return n
+	}
+
+	// 孩子尽量多, 但是每个孩子的元素不能少于minSub
+	minSub := maxSubtree(b.minItems+1, height-1, m)
+	c := (m + 1) / (minSub + 1)
+	if c > b.maxItems+1 {
+		c = b.maxItems + 1
+	}
+
+	n := b.newNode(false)
+	n.items = vec.WithCapacity[pair[K, V]](c - 1)
+	n.children.Reserve(c)
+
+	// 去掉c-1个分隔的元素, 剩下的平均分给c个孩子
+	base, extra := (m-(c-1))/c, (m-(c-1))%c
+	for i := 0; i < c; i++ {
+		size := base
+		if i < extra {
+			size++
+		}
+
+		n.children.Push(b.buildSorted(it, size, height-1))
+		if it.Err() != nil {
+			break
+		}
+		if i != c-1 {
+			n.items.Push(nextPair(it))
+		}
+	}
+	return n
+}
@@ -0,0 +1,102 @@
+package btree
+
+import (
+	"testing"
+)
+
+// 检查每个节点的元素个数和叶子的深度, 返回子树高度
+func checkNodes(t *testing.T, b *Btree[int, int], n *node[int, int], isRoot bool) int {
+	items := n.items.Len()
+	if items > b.maxItems || !isRoot && items < b.minItems {
+		t.Fatalf("node has %d items, want [%d, %d]", items, b.minItems, b.maxItems)
+	}
+	if n.leaf() {
+		return 1
+	}
+
+	if n.children.Len() != items+1 {
+		t.Fatalf("node has %d items and %d children", items, n.children.Len())
+	}
+
+	h := -1
+	for i := 0; i < n.children.Len(); i++ {
+		ch := checkNodes(t, b, n.children.Get(i), false)
+		if h != -1 && ch != h {
+			t.Fatalf("leaves at different depth %d %d", h, ch)
+		}
+		h = ch
+	}
+	return h + 1
+}
+
+func Test_Btree_FromSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 16} {
+		for n := 0; n < 600; n += 7 {
+			keys := make([]int, n)
+			vals := make([]int, n)
+			for i := range keys {
+				keys[i] = i * 2
+				vals[i] = i
+			}
+
+			b, err := FromSorted(degree, keys, vals)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.Len() != n {
+				t.Fatalf("degree:%d, n:%d, len:%d", degree, n, b.Len())
+			}
+			if n == 0 {
+				continue
+			}
+			checkNodes(t, b, b.root, true)
+
+			i := 0
+			b.Range(func(k, v int) bool {
+				if k != keys[i] || v != vals[i] {
+					t.Fatalf("degree:%d, n:%d, index %d: got (%d, %d)", degree, n, i, k, v)
+				}
+				i++
+				return true
+			})
+
+			// 构造好的树可以继续修改
+			b.Set(1, 1)
+			b.Delete(0)
+			checkNodes(t, b, b.root, true)
+			if _, ok := b.TryGet(1); !ok || b.Len() != n {
+				t.Fatalf("degree:%d, n:%d, set/delete after FromSorted failed", degree, n)
+			}
+		}
+	}
+}
+
+func Test_Btree_FromSorted_Error(t *testing.T) {
+	if _, err := FromSorted(2, []int{1, 3, 2}, []int{1, 2, 3}); err != ErrNotSorted {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted(2, []int{1, 2, 2}, []int{1, 2, 3}); err != ErrDuplicateKey {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted(2, []int{1, 2}, []int{1}); err != ErrLenMismatch {
+		t.Fatalf("got %v", err)
+	}
+
+	b, err := FromSortedFunc(0, 1000, func(i int) (int, string) { return i, "" })
+	if err != nil || b.Len() != 1000 {
+		t.Fatalf("len:%d, err:%v", b.Len(), err)
+	}
+
+	// 发现乱序之后不再调用f
+	calls := 0
+	_, err = FromSortedFunc(2, 100, func(i int) (int, int) {
+		calls++
+		if i == 10 {
+			return 0, i
+		}
+		return i, i
+	})
+	if err != ErrNotSorted || calls != 11 {
+		t.Fatalf("err:%v, calls:%d", err, calls)
+	}
+}
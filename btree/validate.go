@@ -0,0 +1,73 @@
+package btree
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+type validator[K constraints.Ordered, V any] struct {
+	b     *Btree[K, V]
+	count int
+	depth int // 叶子的深度, 所有叶子必须一样
+	prev  K
+}
+
+// 中序检查子树
+func (v *validator[K, V]) walk(n *node[K, V], depth int) error {
+	items := 0
+	if n.items != nil {
+		items = n.items.Len()
+	}
+
+	isRoot := n == v.b.root
+	if items > v.b.maxItems || !isRoot && items < v.b.minItems || isRoot && items == 0 {
+		return fmt.Errorf("btree: node at depth %d has %d items, want [%d, %d]", depth, items, v.b.minItems, v.b.maxItems)
+	}
+
+	if n.leaf() {
+		if v.depth == -1 {
+			v.depth = depth
+		} else if v.depth != depth {
+			return fmt.Errorf("btree: leaves at depth %d and %d", v.depth, depth)
+		}
+	} else if n.children.Len() != items+1 {
+		return fmt.Errorf("btree: node at depth %d has %d items but %d children", depth, items, n.children.Len())
+	}
+
+	for i := 0; i <= items; i++ {
+		if !n.leaf() {
+			if err := v.walk(n.children.Get(i), depth+1); err != nil {
+				return err
+			}
+		}
+
+		if i == items {
+			break
+		}
+
+		k := n.items.Get(i).key
+		if v.count > 0 && k <= v.prev {
+			return fmt.Errorf("btree: key %v after %v is out of order", k, v.prev)
+		}
+		v.prev = k
+		v.count++
+	}
+	return nil
+}
+
+// 检查每个节点的元素个数, 叶子的深度, key的顺序和Len(), 有问题返回错误
+func (b *Btree[K, V]) Validate() error {
+	v := &validator[K, V]{b: b, depth: -1}
+	if b.root != nil {
+		if err := v.walk(b.root, 0); err != nil {
+			return err
+		}
+	}
+
+	if v.count != b.count {
+		return fmt.Errorf("btree: Len() is %d, but has %d items", b.count, v.count)
+	}
+	return nil
+}
@@ -7,7 +7,7 @@ import (
 	"unsafe"
 
 	"github.com/antlabs/gstl/api"
-	xxhash "github.com/cespare/xxhash/v2"
+	"github.com/antlabs/gstl/hasher"
 	"golang.org/x/exp/constraints"
 )
 
@@ -20,21 +20,71 @@ type Pair[K constraints.Ordered, V any] struct {
 
 type CMap[K constraints.Ordered, V any] struct {
 	bucket   []Item[K, V]
-	keySize  int
-	isKeyStr bool
+	hash     hasher.Hasher[K]
+	newShard func() api.Map[K, V]
 }
 
 type Item[K constraints.Ordered, V any] struct {
 	rw sync.RWMutex
 	m  api.Map[K, V]
+	// 读操作也加写锁, WithShardFactory指定的map读的时候可能会修改自己
+	// 比如rhashmap的渐进式rehash, splaytree的伸展, 只有标准库的map可以并发读
+	exclusiveRead bool
 }
 
-func New[K constraints.Ordered, V any]() (c *CMap[K, V]) {
-	c = &CMap[K, V]{}
-	c.init(0)
+// 读操作加锁
+func (item *Item[K, V]) rlock() {
+	if item.exclusiveRead {
+		item.rw.Lock()
+		return
+	}
+	item.rw.RLock()
+}
+
+func (item *Item[K, V]) runlock() {
+	if item.exclusiveRead {
+		item.rw.Unlock()
+		return
+	}
+	item.rw.RUnlock()
+}
+
+func New[K constraints.Ordered, V any](opts ...Option) (c *CMap[K, V]) {
+	conf := config{seed: hasher.RandomSeed()}
+	for _, o := range opts {
+		o.apply(&conf)
+	}
+
+	c = &CMap[K, V]{hash: hasher.New[K](conf.seed)}
+	if conf.shardFactory != nil {
+		newShard, ok := conf.shardFactory.(func() api.Map[K, V])
+		if !ok {
+			panic("cmap: WithShardFactory type mismatch")
+		}
+		c.newShard = newShard
+	}
+	switch {
+	case conf.hasher != nil:
+		hs, ok := conf.hasher.(hasher.Hasher[K])
+		if !ok {
+			panic("cmap: WithHasher key type mismatch")
+		}
+		c.hash = hs
+	case conf.hashFunc != nil && reflect.TypeOf(new(K)).Elem().Kind() == reflect.String:
+		hashFunc, seed := conf.hashFunc, conf.seed
+		c.hash = hasher.Func[K](func(key K) uint64 {
+			return hashFunc(seed, *(*string)(unsafe.Pointer(&key)))
+		})
+	}
+	c.init(conf.shards)
 	return c
 }
 
+// 和New一样, 可以用WithShards, WithShardFactory指定分片的个数和分片使用的map
+func NewWithOptions[K constraints.Ordered, V any](opts ...Option) *CMap[K, V] {
+	return New[K, V](opts...)
+}
+
 func (c *CMap[K, V]) init(n int) {
 	np := runtime.GOMAXPROCS(0)
 	if np <= 0 {
@@ -48,6 +98,11 @@ func (c *CMap[K, V]) init(n int) {
 	c.bucket = make([]Item[K, V], np)
 
 	for i := range c.bucket {
+		if c.newShard != nil {
+			c.bucket[i].m = c.newShard()
+			c.bucket[i].exclusiveRead = true
+			continue
+		}
 		c.bucket[i].m = newStdMap[K, V]()
 	}
 
@@ -55,31 +110,7 @@ func (c *CMap[K, V]) init(n int) {
 
 // 计算hash值
 func (c *CMap[K, V]) calHash(k K) uint64 {
-	var key string
-
-	if c.isKeyStr {
-		// 直接赋值会报错, 使用unsafe绕过编译器检查
-		key = *(*string)(unsafe.Pointer(&k))
-	} else {
-		// 因为xxhash.Sum64String 接收string, 所以要把非string类型变量当成string类型来处理
-		key = *(*string)(unsafe.Pointer(&reflect.StringHeader{
-			Data: uintptr(unsafe.Pointer(&k)),
-			Len:  c.keySize,
-		}))
-	}
-
-	return xxhash.Sum64String(key)
-}
-
-// 保存key的类型和key的长度
-func (h *CMap[K, V]) keyTypeAndKeySize() {
-	var k K
-	switch (interface{})(k).(type) {
-	case string:
-		h.isKeyStr = true
-	default:
-		h.keySize = int(unsafe.Sizeof(k))
-	}
+	return c.hash.Hash(k)
 }
 
 // 找到索引
@@ -111,9 +142,9 @@ func (c *CMap[K, V]) UpdateOrInsert(k K, cb UpdataOrInsertCb[K, V]) {
 
 func (c *CMap[K, V]) Load(key K) (value V, ok bool) {
 	item := c.findIndex(key)
-	item.rw.RLock()
+	item.rlock()
 	value, ok = item.m.TryGet(key)
-	item.rw.RUnlock()
+	item.runlock()
 	return
 }
 
@@ -149,9 +180,9 @@ func (c *CMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
 func (c *CMap[K, V]) Range(f func(key K, value V) bool) {
 	for i := 0; i < len(c.bucket); i++ {
 		item := &c.bucket[i]
-		item.rw.RLock()
+		item.rlock()
 		item.m.Range(f)
-		item.rw.RUnlock()
+		item.runlock()
 	}
 }
 
@@ -172,12 +203,12 @@ func (c *CMap[K, V]) Iter() (rv chan Pair[K, V]) {
 		go func(item *Item[K, V]) {
 
 			defer wg.Done()
-			item.rw.RLock()
+			item.rlock()
 			item.m.Range(func(key K, value V) bool {
 				rv <- Pair[K, V]{Key: key, Val: value}
 				return true
 			})
-			item.rw.RUnlock()
+			item.runlock()
 
 		}(item)
 	}
@@ -204,12 +235,12 @@ func (c *CMap[K, V]) Keys() []K {
 	for i := 0; i < len(c.bucket); i++ {
 
 		item := &c.bucket[i]
-		item.rw.RLock()
+		item.rlock()
 		item.m.Range(func(key K, value V) bool {
 			all = append(all, key)
 			return true
 		})
-		item.rw.RUnlock()
+		item.runlock()
 	}
 	return all
 }
@@ -224,12 +255,12 @@ func (c *CMap[K, V]) Values() []V {
 	for i := 0; i < len(c.bucket); i++ {
 
 		item := &c.bucket[i]
-		item.rw.RLock()
+		item.rlock()
 		item.m.Range(func(key K, value V) bool {
 			all = append(all, value)
 			return true
 		})
-		item.rw.RUnlock()
+		item.runlock()
 	}
 	return all
 }
@@ -238,9 +269,9 @@ func (c *CMap[K, V]) Len() int {
 	l := 0
 	for i := 0; i < len(c.bucket); i++ {
 		item := &c.bucket[i]
-		item.rw.RLock()
+		item.rlock()
 		l += item.m.Len()
-		item.rw.RUnlock()
+		item.runlock()
 	}
 	return l
 }
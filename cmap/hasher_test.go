@@ -0,0 +1,76 @@
+package cmap
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/hasher"
+)
+
+// 相等的string内存不同也要落到同一个分片
+func Test_StringKey(t *testing.T) {
+	m := New[string, int]()
+	for i := 0; i < 100; i++ {
+		m.Store(strings.Repeat("k", i), i)
+	}
+	for i := 0; i < 100; i++ {
+		m.Store(strings.Repeat("k", i), i*2)
+	}
+	if m.Len() != 100 {
+		t.Fatalf("len %d", m.Len())
+	}
+	for i := 0; i < 100; i++ {
+		if v, ok := m.Load(strings.Repeat("k", i)); !ok || v != i*2 {
+			t.Fatalf("load %d got (%d, %v)", i, v, ok)
+		}
+	}
+}
+
+func Test_FloatKey(t *testing.T) {
+	m := New[float64, int]()
+	m.Store(0, 1)
+	if v, ok := m.Load(math.Copysign(0, -1)); !ok || v != 1 {
+		t.Fatalf("load -0 got (%d, %v)", v, ok)
+	}
+}
+
+func Test_WithHasher(t *testing.T) {
+	calls := 0
+	m := New[string, int](WithHasher[string](hasher.Func[string](func(k string) uint64 {
+		calls++
+		return uint64(len(k))
+	})))
+	m.Store("a", 1)
+	if v, _ := m.Load("a"); v != 1 || calls != 2 {
+		t.Fatalf("got %d, calls %d", v, calls)
+	}
+}
+
+func Test_WithSeed(t *testing.T) {
+	// 相同的seed, 分片是一样的
+	a := New[string, int](WithSeed(1))
+	b := New[string, int](WithSeed(1))
+	for i := 0; i < 100; i++ {
+		k := strings.Repeat("k", i)
+		if a.findIndex(k) != &a.bucket[a.calHash(k)%uint64(len(a.bucket))] || a.calHash(k) != b.calHash(k) {
+			t.Fatalf("key %q hash differently", k)
+		}
+	}
+	if New[string, int](WithSeed(2)).calHash("k") == a.calHash("k") {
+		t.Fatal("seed not used")
+	}
+	if New[string, int]().calHash("k") == New[string, int]().calHash("k") {
+		t.Fatal("default seed not random")
+	}
+
+	var seeds []uint64
+	m := New[string, int](WithSeed(3), WithSeededHashFunc(func(seed uint64, k string) uint64 {
+		seeds = append(seeds, seed)
+		return 0
+	}))
+	m.Store("a", 1)
+	if v, _ := m.Load("a"); v != 1 || len(seeds) != 2 || seeds[0] != 3 {
+		t.Fatalf("seeds %v", seeds)
+	}
+}
@@ -0,0 +1,89 @@
+package cmap
+
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/hasher"
+	"golang.org/x/exp/constraints"
+)
+
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	hasher       any // hasher.Hasher[K]
+	hashFunc     func(seed uint64, str string) uint64
+	seed         uint64
+	shards       int
+	shardFactory any // func() api.Map[K, V]
+}
+
+type withHasher struct {
+	h any
+}
+
+func (w withHasher) apply(c *config) {
+	c.hasher = w.h
+}
+
+// 自定义key的hash函数, 相等的key必须返回相同的hash值
+// K必须和map的key类型一致
+func WithHasher[K comparable](h hasher.Hasher[K]) Option {
+	return withHasher{h: h}
+}
+
+type hashFunc func(seed uint64, str string) uint64
+
+func (h hashFunc) apply(c *config) {
+	c.hashFunc = h
+}
+
+// 自定义字符串的hash函数, 只对string类型的key生效
+func WithHashFunc(hfunc func(str string) uint64) Option {
+	return hashFunc(func(_ uint64, str string) uint64 {
+		return hfunc(str)
+	})
+}
+
+// 和WithHashFunc一样, 多传一个这个map的seed, 用来防止构造大量冲突的key攻击
+func WithSeededHashFunc(hfunc func(seed uint64, str string) uint64) Option {
+	return hashFunc(hfunc)
+}
+
+type withSeed uint64
+
+func (s withSeed) apply(c *config) {
+	c.seed = uint64(s)
+}
+
+// 指定hash的seed, 默认每个map随机生成, 测试的时候可以用来复现
+func WithSeed(seed uint64) Option {
+	return withSeed(seed)
+}
+
+type withShards int
+
+func (w withShards) apply(c *config) {
+	c.shards = int(w)
+}
+
+// 分片的个数, 默认是GOMAXPROCS
+func WithShards(n int) Option {
+	return withShards(n)
+}
+
+type withShardFactory struct {
+	f any
+}
+
+func (w withShardFactory) apply(c *config) {
+	c.shardFactory = w.f
+}
+
+// 每个分片使用的map, 默认是标准库的map. K, V必须和CMap的一致
+// 很多map读的时候也会修改自己(rhashmap的渐进式rehash, splaytree的伸展), 所以这些分片的读操作也加写锁
+// 同一个分片上的读不能并发, 读多的场景可以多分一些片
+// 使用btree, skiplist这些有序的map时, RangeSorted不需要再排序
+func WithShardFactory[K constraints.Ordered, V any](f func() api.Map[K, V]) Option {
+	return withShardFactory{f: f}
+}
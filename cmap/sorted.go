@@ -0,0 +1,78 @@
+package cmap
+
+// 按key的顺序遍历所有分片
+// 分片之间的key不会重复, 每个分片先拷贝成有序的slice, 然后用小顶堆做k路归并
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+// 分片的拷贝, pos是下一个要遍历的位置
+type sortedShard[K constraints.Ordered, V any] struct {
+	pairs []Pair[K, V]
+	pos   int
+}
+
+type shardHeap[K constraints.Ordered, V any] []*sortedShard[K, V]
+
+func (h shardHeap[K, V]) Len() int { return len(h) }
+func (h shardHeap[K, V]) Less(i, j int) bool {
+	return h[i].pairs[h[i].pos].Key < h[j].pairs[h[j].pos].Key
+}
+func (h shardHeap[K, V]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *shardHeap[K, V]) Push(x any)   { *h = append(*h, x.(*sortedShard[K, V])) }
+func (h *shardHeap[K, V]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// 拷贝一个分片, 有序的map(api.SortedMap)本身就是按key遍历的, 其他的map需要排序
+func (item *Item[K, V]) sorted() []Pair[K, V] {
+	item.rlock()
+	pairs := make([]Pair[K, V], 0, item.m.Len())
+	item.m.Range(func(k K, v V) bool {
+		pairs = append(pairs, Pair[K, V]{Key: k, Val: v})
+		return true
+	})
+	_, ok := item.m.(api.SortedMap[K, V])
+	item.runlock()
+
+	if !ok {
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].Key < pairs[j].Key
+		})
+	}
+	return pairs
+}
+
+// 从小到大遍历, 回调返回false时停止
+// 每个分片是分别加锁拷贝的, 和Range一样不是整个map的快照, 回调里面可以修改map
+func (c *CMap[K, V]) RangeSorted(f func(key K, value V) bool) {
+	h := make(shardHeap[K, V], 0, len(c.bucket))
+	for i := range c.bucket {
+		if pairs := c.bucket[i].sorted(); len(pairs) > 0 {
+			h = append(h, &sortedShard[K, V]{pairs: pairs})
+		}
+	}
+	heap.Init(&h)
+
+	for len(h) > 0 {
+		s := h[0]
+		p := s.pairs[s.pos]
+		if !f(p.Key, p.Val) {
+			return
+		}
+
+		s.pos++
+		if s.pos == len(s.pairs) {
+			heap.Pop(&h)
+			continue
+		}
+		heap.Fix(&h, 0)
+	}
+}
@@ -0,0 +1,132 @@
+package cmap
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/btree"
+	"github.com/antlabs/gstl/rhashmap"
+	"github.com/antlabs/gstl/skiplist"
+)
+
+func Test_WithShards(t *testing.T) {
+	m := NewWithOptions[int, int](WithShards(3))
+	if len(m.bucket) != 3 {
+		t.Fatalf("got %d shards", len(m.bucket))
+	}
+}
+
+func Test_WithShardFactory_Mismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expect panic")
+		}
+	}()
+	NewWithOptions[int, int](WithShardFactory(func() api.Map[string, int] {
+		return btree.New[string, int](0)
+	}))
+}
+
+func Test_RangeSorted(t *testing.T) {
+	factories := map[string]func() api.Map[int, int]{
+		"std": nil,
+		"rhashmap": func() api.Map[int, int] {
+			return rhashmap.New[int, int]()
+		},
+		"btree": func() api.Map[int, int] {
+			return btree.New[int, int](0)
+		},
+		"skiplist": func() api.Map[int, int] {
+			return skiplist.New[int, int]()
+		},
+	}
+
+	for name, f := range factories {
+		opts := []Option{WithShards(7)}
+		if f != nil {
+			opts = append(opts, WithShardFactory(f))
+		}
+		m := NewWithOptions[int, int](opts...)
+		if f != nil {
+			if _, ok := m.bucket[0].m.(*stdmap[int, int]); ok {
+				t.Fatalf("%s: shard factory not used", name)
+			}
+		}
+
+		r := rand.New(rand.NewSource(1))
+		want := make(map[int]int)
+		for i := 0; i < 3000; i++ {
+			k := r.Intn(1000)
+			if r.Intn(4) == 0 {
+				m.Delete(k)
+				delete(want, k)
+				continue
+			}
+			m.Store(k, i)
+			want[k] = i
+		}
+
+		prev, n := -1, 0
+		m.RangeSorted(func(k, v int) bool {
+			if k <= prev {
+				t.Fatalf("%s: key %d after %d", name, k, prev)
+			}
+			if want[k] != v {
+				t.Fatalf("%s: key %d got %d, want %d", name, k, v, want[k])
+			}
+			prev = k
+			n++
+			return true
+		})
+		if n != len(want) || m.Len() != len(want) {
+			t.Fatalf("%s: range %d, len %d, want %d", name, n, m.Len(), len(want))
+		}
+
+		n = 0
+		m.RangeSorted(func(k, v int) bool {
+			n++
+			return n < 10
+		})
+		if n != 10 {
+			t.Fatalf("%s: stop at %d", name, n)
+		}
+	}
+}
+
+// rhashmap读的时候会做渐进式rehash, 分片上的并发读不能有数据竞争, 用go test -race检查
+func Test_WithShardFactory_ConcurrentRead(t *testing.T) {
+	m := NewWithOptions[int, int](WithShards(1), WithShardFactory(func() api.Map[int, int] {
+		return rhashmap.New[int, int]()
+	}))
+	// 写完之后最后一次扩容的rehash还没有结束, 后面的读操作会继续搬迁桶
+	const n = 100000
+	for i := 0; i < n; i++ {
+		m.Store(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				if v, ok := m.Load(i); !ok || v != i {
+					t.Errorf("load %d got (%d, %v)", i, v, ok)
+					return
+				}
+				if i == n/2 {
+					m.Len()
+					m.Range(func(k, v int) bool { return true })
+					m.RangeSorted(func(k, v int) bool { return true })
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if keys := m.Keys(); len(keys) != n {
+		t.Fatalf("got %d keys", len(keys))
+	}
+}
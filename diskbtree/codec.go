@@ -0,0 +1,125 @@
+package diskbtree
+
+// apache 2.0 antlabs
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+var errShortBuffer = errors.New("diskbtree: short buffer")
+
+// 把key或者value编码成[]byte, 保存到页里面
+type Codec[T any] interface {
+	// 编码之后append到dst后面
+	Encode(dst []byte, v T) ([]byte, error)
+	Decode(b []byte) (T, error)
+}
+
+// 整数, 使用变长编码
+type IntCodec[T constraints.Integer] struct{}
+
+func (IntCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	return binary.AppendVarint(dst, int64(v)), nil
+}
+
+func (IntCodec[T]) Decode(b []byte) (T, error) {
+	v, n := binary.Varint(b)
+	if n <= 0 {
+		return 0, errShortBuffer
+	}
+	return T(v), nil
+}
+
+// 浮点数, 固定8个字节
+type FloatCodec[T constraints.Float] struct{}
+
+func (FloatCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(float64(v))), nil
+}
+
+func (FloatCodec[T]) Decode(b []byte) (T, error) {
+	if len(b) < 8 {
+		return 0, errShortBuffer
+	}
+	return T(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+}
+
+// 字符串, 直接保存
+type StringCodec[T ~string] struct{}
+
+func (StringCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (StringCodec[T]) Decode(b []byte) (T, error) {
+	return T(b), nil
+}
+
+// []byte, 解码的时候会复制一份
+type BytesCodec struct{}
+
+func (BytesCodec) Encode(dst []byte, v []byte) ([]byte, error) {
+	return append(dst, v...), nil
+}
+
+func (BytesCodec) Decode(b []byte) ([]byte, error) {
+	return append([]byte(nil), b...), nil
+}
+
+// 任意类型, 使用encoding/json
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(dst []byte, v T) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(dst, b...), nil
+}
+
+func (JSONCodec[T]) Decode(b []byte) (v T, err error) {
+	err = json.Unmarshal(b, &v)
+	return
+}
+
+// 常见的类型使用对应的codec, 其他类型使用json
+func defaultCodec[T any]() Codec[T] {
+	var c any
+	switch any(*new(T)).(type) {
+	case int:
+		c = IntCodec[int]{}
+	case int8:
+		c = IntCodec[int8]{}
+	case int16:
+		c = IntCodec[int16]{}
+	case int32:
+		c = IntCodec[int32]{}
+	case int64:
+		c = IntCodec[int64]{}
+	case uint:
+		c = IntCodec[uint]{}
+	case uint8:
+		c = IntCodec[uint8]{}
+	case uint16:
+		c = IntCodec[uint16]{}
+	case uint32:
+		c = IntCodec[uint32]{}
+	case uint64:
+		c = IntCodec[uint64]{}
+	case float32:
+		c = FloatCodec[float32]{}
+	case float64:
+		c = FloatCodec[float64]{}
+	case string:
+		c = StringCodec[string]{}
+	case []byte:
+		c = BytesCodec{}
+	default:
+		return JSONCodec[T]{}
+	}
+	return c.(Codec[T])
+}
@@ -0,0 +1,538 @@
+package diskbtree
+
+// apache 2.0 antlabs
+
+// 基于文件的b tree, 每个节点占一个固定大小的页
+// 修改先留在内存的脏页里面, Sync的时候通过wal原子的写回文件
+// 脏页在Sync之前不会被淘汰, 没有设置DirtyLimit时, 两次Sync之间修改的页越多占用的内存越多
+import (
+	"errors"
+	"slices"
+
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/cmp"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*DiskBtree[int, int])(nil)
+
+var (
+	ErrCorrupt  = errors.New("diskbtree: corrupt file")
+	ErrTooLarge = errors.New("diskbtree: item too large")
+	ErrClosed   = errors.New("diskbtree: closed")
+)
+
+const (
+	minPageSize      = 256
+	maxPageSize      = 1 << 16
+	defaultPageSize  = 4096
+	defaultCacheSize = 256
+)
+
+// 打开时的选项
+type Options[K constraints.Ordered, V any] struct {
+	PageSize   int      // 页大小, 只在新建文件时生效, 默认4096
+	CacheSize  int      // 最多缓存多少个干净页, 默认256
+	DirtyLimit int      // 脏页超过这个数量时写操作结束后自动Sync, 0表示不限制, 只在调用Sync时写回
+	KeyCodec   Codec[K] // 为nil时按类型选择默认的编码
+	ValueCodec Codec[V] // 为nil时按类型选择默认的编码
+}
+
+// 基于文件的b tree
+// 遇到IO或者解码错误之后, 后面的操作都不会生效, 错误通过Err, Sync, Close返回
+// 单个元素编码失败或者太大只会拒绝这一次写入, 不算在里面
+type DiskBtree[K constraints.Ordered, V any] struct {
+	p   *pager[K, V]
+	err error
+}
+
+// 打开或者创建path, 同时会使用path+".wal"作为日志文件
+func Open[K constraints.Ordered, V any](path string, opts *Options[K, V]) (*DiskBtree[K, V], error) {
+	var o Options[K, V]
+	if opts != nil {
+		o = *opts
+	}
+	if o.PageSize == 0 {
+		o.PageSize = defaultPageSize
+	}
+	if o.PageSize < minPageSize || o.PageSize > maxPageSize {
+		return nil, errors.New("diskbtree: invalid page size")
+	}
+	if o.CacheSize <= 0 {
+		o.CacheSize = defaultCacheSize
+	}
+	if o.KeyCodec == nil {
+		o.KeyCodec = defaultCodec[K]()
+	}
+	if o.ValueCodec == nil {
+		o.ValueCodec = defaultCodec[V]()
+	}
+
+	p, err := openPager(path, &o)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskBtree[K, V]{p: p}, nil
+}
+
+// 记录第一个错误
+func (t *DiskBtree[K, V]) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// 返回第一个遇到的错误
+func (t *DiskBtree[K, V]) Err() error {
+	return t.err
+}
+
+// 把所有修改写到文件
+func (t *DiskBtree[K, V]) Sync() error {
+	if t.err != nil {
+		return t.err
+	}
+	t.fail(t.p.sync())
+	return t.err
+}
+
+// Sync之后关闭文件
+func (t *DiskBtree[K, V]) Close() error {
+	if t.err == ErrClosed {
+		return t.err
+	}
+	err := t.Sync()
+	if err2 := t.p.close(); err == nil {
+		err = err2
+	}
+	t.err = ErrClosed
+	return err
+}
+
+// 写操作结束之后调用, 脏页太多时提前落盘, 再淘汰多余的干净页
+func (t *DiskBtree[K, V]) afterWrite() {
+	if t.err == nil && t.p.dirtyLimit > 0 && len(t.p.dirty) >= t.p.dirtyLimit {
+		t.fail(t.p.sync())
+	}
+	t.p.evict()
+}
+
+// 元素个数
+func (t *DiskBtree[K, V]) Len() int {
+	return int(t.p.meta.count)
+}
+
+// 单个元素编码之后的最大长度, 保证一页至少能放下4个元素
+func (t *DiskBtree[K, V]) maxItem() int {
+	return (t.p.pageSize() - 64) / 4
+}
+
+// 获取
+func (t *DiskBtree[K, V]) Get(k K) (v V) {
+	v, _ = t.TryGet(k)
+	return
+}
+
+// 获取
+func (t *DiskBtree[K, V]) TryGet(k K) (v V, ok bool) {
+	if t.err != nil || t.p.meta.root == 0 {
+		return
+	}
+	defer t.p.evict()
+
+	id := t.p.meta.root
+	for {
+		n, err := t.p.get(id)
+		if err != nil {
+			t.fail(err)
+			return
+		}
+		i, found := n.find(k)
+		if found {
+			return n.items[i].val, true
+		}
+		if n.leaf() {
+			return
+		}
+		id = n.children[i]
+	}
+}
+
+// 设置
+func (t *DiskBtree[K, V]) Set(k K, v V) {
+	t.Swap(k, v)
+}
+
+// 设置, 编码失败或者编码之后超过页大小的1/4时返回错误
+// 这种错误只影响这一次调用, 树不会被修改, 后面的操作照常进行
+func (t *DiskBtree[K, V]) Put(k K, v V) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	enc, err := encodeItem(t.p.kc, t.p.vc, k, v)
+	if err != nil {
+		return err
+	}
+	if len(enc) > t.maxItem() {
+		return ErrTooLarge
+	}
+	t.swap(item[K, V]{key: k, val: v, enc: enc})
+	return t.err
+}
+
+// 设置值, 返回老的值
+// 编码失败或者太大的元素直接忽略, 树不会被修改, 需要知道原因用Put
+func (t *DiskBtree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	if t.err != nil {
+		return
+	}
+
+	enc, err := encodeItem(t.p.kc, t.p.vc, k, v)
+	if err != nil || len(enc) > t.maxItem() {
+		return
+	}
+	return t.swap(item[K, V]{key: k, val: v, enc: enc})
+}
+
+// 插入编码好的元素, 只有IO和解码错误会记录到t.err
+func (t *DiskBtree[K, V]) swap(it item[K, V]) (prev V, replaced bool) {
+	defer t.afterWrite()
+
+	if t.p.meta.root == 0 {
+		n, err := t.p.alloc(kindLeaf)
+		if err != nil {
+			t.fail(err)
+			return
+		}
+		n.items = []item[K, V]{it}
+		t.p.meta.root = n.id
+		t.p.meta.count++
+		return
+	}
+
+	root, err := t.p.get(t.p.meta.root)
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	prev, replaced, err = t.insert(root, it)
+	if err == nil {
+		err = t.fixRoot()
+	}
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	if !replaced {
+		t.p.meta.count++
+	}
+	return
+}
+
+func (t *DiskBtree[K, V]) insert(n *node[K, V], it item[K, V]) (prev V, replaced bool, err error) {
+	i, found := n.find(it.key)
+	if found {
+		t.p.markDirty(n)
+		prev = n.items[i].val
+		n.items[i] = it
+		return prev, true, nil
+	}
+
+	if n.leaf() {
+		t.p.markDirty(n)
+		n.items = slices.Insert(n.items, i, it)
+		return
+	}
+
+	child, err := t.p.get(n.children[i])
+	if err != nil {
+		return
+	}
+	if prev, replaced, err = t.insert(child, it); err != nil {
+		return
+	}
+	return prev, replaced, t.fixChild(n, i)
+}
+
+// 删除
+func (t *DiskBtree[K, V]) Delete(k K) {
+	t.DeleteWithPrev(k)
+}
+
+// 删除, 返回被删除的值
+func (t *DiskBtree[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
+	if t.err != nil || t.p.meta.root == 0 {
+		return
+	}
+	defer t.afterWrite()
+
+	root, err := t.p.get(t.p.meta.root)
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	prev, deleted, err = t.delete(root, k)
+	if err == nil && deleted {
+		err = t.fixRoot()
+	}
+	if err != nil {
+		t.fail(err)
+		return
+	}
+	if deleted {
+		t.p.meta.count--
+	}
+	return
+}
+
+func (t *DiskBtree[K, V]) delete(n *node[K, V], k K) (prev V, deleted bool, err error) {
+	i, found := n.find(k)
+	if n.leaf() {
+		if !found {
+			return
+		}
+		t.p.markDirty(n)
+		prev = n.items[i].val
+		n.items = slices.Delete(n.items, i, i+1)
+		return prev, true, nil
+	}
+
+	child, err := t.p.get(n.children[i])
+	if err != nil {
+		return
+	}
+	if found {
+		// 用左子树最大的元素替换
+		var max item[K, V]
+		if max, err = t.deleteMax(child); err != nil {
+			return
+		}
+		t.p.markDirty(n)
+		prev, deleted = n.items[i].val, true
+		n.items[i] = max
+	} else {
+		if prev, deleted, err = t.delete(child, k); err != nil || !deleted {
+			return
+		}
+	}
+	return prev, deleted, t.fixChild(n, i)
+}
+
+// 删除子树里面最大的元素
+func (t *DiskBtree[K, V]) deleteMax(n *node[K, V]) (max item[K, V], err error) {
+	if n.leaf() {
+		t.p.markDirty(n)
+		max = n.items[len(n.items)-1]
+		n.items = n.items[:len(n.items)-1]
+		return max, nil
+	}
+
+	last := len(n.children) - 1
+	child, err := t.p.get(n.children[last])
+	if err != nil {
+		return
+	}
+	if max, err = t.deleteMax(child); err != nil {
+		return
+	}
+	return max, t.fixChild(n, last)
+}
+
+// 修改了n的第i个孩子之后调用, 太大就分裂, 太小就和兄弟节点合并或者重新分配
+func (t *DiskBtree[K, V]) fixChild(n *node[K, V], i int) error {
+	child, err := t.p.get(n.children[i])
+	if err != nil {
+		return err
+	}
+
+	size := child.size()
+	if size > t.p.pageSize() {
+		return t.split(n, i, child)
+	}
+	if size < t.p.pageSize()/4 && len(n.children) > 1 {
+		return t.rebalance(n, i)
+	}
+	return nil
+}
+
+// 按字节数找到中间的元素
+func splitPoint[K constraints.Ordered, V any](n *node[K, V]) int {
+	half := n.size() / 2
+	acc := nodeHeader
+	if !n.leaf() {
+		acc += 4
+	}
+	mid := 0
+	for ; mid < len(n.items); mid++ {
+		acc += len(n.items[mid].enc)
+		if !n.leaf() {
+			acc += 4
+		}
+		if acc >= half {
+			break
+		}
+	}
+	return cmp.Min(cmp.Max(mid, 1), len(n.items)-2)
+}
+
+// 分裂n的第i个孩子
+func (t *DiskBtree[K, V]) split(n *node[K, V], i int, child *node[K, V]) error {
+	right, err := t.p.alloc(child.kind)
+	if err != nil {
+		return err
+	}
+	t.p.markDirty(child)
+	t.p.markDirty(n)
+
+	mid := splitPoint(child)
+	median := child.items[mid]
+	right.items = slices.Clone(child.items[mid+1:])
+	child.items = slices.Clip(child.items[:mid])
+	if !child.leaf() {
+		right.children = slices.Clone(child.children[mid+1:])
+		child.children = slices.Clip(child.children[:mid+1])
+	}
+
+	n.items = slices.Insert(n.items, i, median)
+	n.children = slices.Insert(n.children, i+1, right.id)
+	return nil
+}
+
+// 和相邻的兄弟节点合并, 放不下一页就重新平均分配
+func (t *DiskBtree[K, V]) rebalance(n *node[K, V], i int) error {
+	if i == len(n.children)-1 {
+		i--
+	}
+	left, err := t.p.get(n.children[i])
+	if err != nil {
+		return err
+	}
+	right, err := t.p.get(n.children[i+1])
+	if err != nil {
+		return err
+	}
+	t.p.markDirty(n)
+	t.p.markDirty(left)
+	t.p.markDirty(right)
+
+	all := &node[K, V]{kind: left.kind}
+	all.items = slices.Concat(left.items, []item[K, V]{n.items[i]}, right.items)
+	all.children = slices.Concat(left.children, right.children)
+
+	if all.size() <= t.p.pageSize() {
+		left.items, left.children = all.items, all.children
+		n.items = slices.Delete(n.items, i, i+1)
+		n.children = slices.Delete(n.children, i+1, i+2)
+		t.p.free(right)
+		return nil
+	}
+
+	mid := splitPoint(all)
+	left.items = all.items[:mid:mid]
+	n.items[i] = all.items[mid]
+	right.items = slices.Clone(all.items[mid+1:])
+	if !all.leaf() {
+		left.children = all.children[: mid+1 : mid+1]
+		right.children = slices.Clone(all.children[mid+1:])
+	}
+	return nil
+}
+
+// 根节点太大就长高一层, 空了就降低一层
+func (t *DiskBtree[K, V]) fixRoot() error {
+	root, err := t.p.get(t.p.meta.root)
+	if err != nil {
+		return err
+	}
+
+	if root.size() > t.p.pageSize() {
+		n, err := t.p.alloc(kindInternal)
+		if err != nil {
+			return err
+		}
+		n.children = []uint32{root.id}
+		t.p.meta.root = n.id
+		return t.split(n, 0, root)
+	}
+
+	if len(root.items) == 0 {
+		t.p.meta.root = 0
+		if !root.leaf() {
+			t.p.meta.root = root.children[0]
+		}
+		t.p.free(root)
+	}
+	return nil
+}
+
+// 升序遍历
+func (t *DiskBtree[K, V]) Range(callback func(k K, v V) bool) {
+	if t.err != nil || t.p.meta.root == 0 {
+		return
+	}
+	_, err := t.rangeInner(t.p.meta.root, false, callback)
+	t.fail(err)
+}
+
+// 降序遍历
+func (t *DiskBtree[K, V]) RangePrev(callback func(k K, v V) bool) {
+	if t.err != nil || t.p.meta.root == 0 {
+		return
+	}
+	_, err := t.rangeInner(t.p.meta.root, true, callback)
+	t.fail(err)
+}
+
+func (t *DiskBtree[K, V]) rangeInner(id uint32, prev bool, callback func(k K, v V) bool) (bool, error) {
+	n, err := t.p.get(id)
+	if err != nil {
+		return false, err
+	}
+	// 只读遍历, 手上拿着的节点被淘汰了也没关系
+	t.p.evict()
+
+	for j := 0; j <= len(n.items); j++ {
+		i := j
+		if prev {
+			i = len(n.items) - j
+		}
+		if !n.leaf() {
+			if ok, err := t.rangeInner(n.children[i], prev, callback); !ok || err != nil {
+				return false, err
+			}
+		}
+		if j == len(n.items) {
+			break
+		}
+		if prev {
+			i--
+		}
+		if !callback(n.items[i].key, n.items[i].val) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// 返回最小的n个值, 升序返回, 比如0,1,2,3, callback返回false时提前结束
+func (t *DiskBtree[K, V]) TopMin(limit int, callback func(k K, v V) bool) {
+	t.Range(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+		limit--
+		return callback(k, v)
+	})
+}
+
+// 返回最大的n个值, 降序返回, 10, 9, 8, 7, callback返回false时提前结束
+func (t *DiskBtree[K, V]) TopMax(limit int, callback func(k K, v V) bool) {
+	t.RangePrev(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+		limit--
+		return callback(k, v)
+	})
+}
@@ -0,0 +1,487 @@
+package diskbtree
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 检查每一页的大小, key的顺序和叶子的深度, 返回子树高度
+func checkPages(t *testing.T, b *DiskBtree[int, string], id uint32, isRoot bool, lo, hi *int) int {
+	n, err := b.p.get(id)
+	if err != nil {
+		t.Fatalf("get page %d: %v", id, err)
+	}
+	if n.size() > b.p.pageSize() {
+		t.Fatalf("page %d size %d > %d", id, n.size(), b.p.pageSize())
+	}
+	if !isRoot && len(n.items) == 0 {
+		t.Fatalf("page %d is empty", id)
+	}
+	for i, it := range n.items {
+		if i > 0 && n.items[i-1].key >= it.key || lo != nil && it.key <= *lo || hi != nil && it.key >= *hi {
+			t.Fatalf("page %d key %d out of order", id, it.key)
+		}
+	}
+	if n.leaf() {
+		return 1
+	}
+
+	if len(n.children) != len(n.items)+1 {
+		t.Fatalf("page %d has %d items and %d children", id, len(n.items), len(n.children))
+	}
+	h := -1
+	for i, c := range n.children {
+		l, r := lo, hi
+		if i > 0 {
+			l = &n.items[i-1].key
+		}
+		if i < len(n.items) {
+			r = &n.items[i].key
+		}
+		ch := checkPages(t, b, c, false, l, r)
+		if h != -1 && ch != h {
+			t.Fatalf("leaves at different depth %d %d", h, ch)
+		}
+		h = ch
+	}
+	return h + 1
+}
+
+func checkTree(t *testing.T, b *DiskBtree[int, string], want map[int]string) {
+	if b.Err() != nil {
+		t.Fatal(b.Err())
+	}
+	if b.Len() != len(want) {
+		t.Fatalf("len %d, want %d", b.Len(), len(want))
+	}
+	if b.p.meta.root != 0 {
+		checkPages(t, b, b.p.meta.root, true, nil, nil)
+	}
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	i := 0
+	b.Range(func(k int, v string) bool {
+		if i >= len(keys) || k != keys[i] || v != want[k] {
+			t.Fatalf("range got %d=%q at %d", k, v, i)
+		}
+		i++
+		return true
+	})
+	if i != len(keys) {
+		t.Fatalf("range visited %d, want %d", i, len(keys))
+	}
+
+	i = len(keys)
+	b.RangePrev(func(k int, v string) bool {
+		i--
+		if k != keys[i] {
+			t.Fatalf("range prev got %d, want %d", k, keys[i])
+		}
+		return true
+	})
+}
+
+func openTest(t *testing.T, path string) *DiskBtree[int, string] {
+	b, err := Open(path, &Options[int, string]{PageSize: 256, CacheSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func randValue(r *rand.Rand) string {
+	return strings.Repeat(string(rune('a'+r.Intn(26))), r.Intn(40))
+}
+
+func Test_DiskBtree_Random(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b := openTest(t, path)
+	r := rand.New(rand.NewSource(1))
+	want := make(map[int]string)
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		switch r.Intn(3) {
+		case 0, 1:
+			v := randValue(r)
+			prev, replaced := b.Swap(k, v)
+			old, ok := want[k]
+			if replaced != ok || prev != old {
+				t.Fatalf("swap %d got (%q, %v), want (%q, %v)", k, prev, replaced, old, ok)
+			}
+			want[k] = v
+		case 2:
+			prev, deleted := b.DeleteWithPrev(k)
+			old, ok := want[k]
+			if deleted != ok || prev != old {
+				t.Fatalf("delete %d got (%q, %v), want (%q, %v)", k, prev, deleted, old, ok)
+			}
+			delete(want, k)
+		}
+
+		if v, ok := b.TryGet(k); ok != hasKey(want, k) || v != want[k] {
+			t.Fatalf("get %d got %q", k, v)
+		}
+		if i%500 == 0 {
+			checkTree(t, b, want)
+			if err := b.Sync(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	checkTree(t, b, want)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b = openTest(t, path)
+	checkTree(t, b, want)
+
+	// 全部删掉之后空闲页会被重用, 文件不会变大
+	for k := range want {
+		b.Delete(k)
+	}
+	checkTree(t, b, nil)
+	pages := b.p.meta.pageCount
+	for k := 0; k < 300; k++ {
+		b.Set(k, "x")
+	}
+	if b.p.meta.pageCount != pages {
+		t.Fatalf("page count grew from %d to %d", pages, b.p.meta.pageCount)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hasKey(m map[int]string, k int) bool {
+	_, ok := m[k]
+	return ok
+}
+
+func Test_DiskBtree_Top(t *testing.T) {
+	b := openTest(t, filepath.Join(t.TempDir(), "data"))
+	defer b.Close()
+	for i := 0; i < 100; i++ {
+		b.Set(i, "v")
+	}
+
+	var got []int
+	b.TopMin(3, func(k int, v string) bool {
+		got = append(got, k)
+		return true
+	})
+	b.TopMax(3, func(k int, v string) bool {
+		got = append(got, k)
+		return true
+	})
+	if want := []int{0, 1, 2, 99, 98, 97}; !equalSlices(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// callback返回false提前结束
+	got = got[:0]
+	b.TopMin(10, func(k int, v string) bool {
+		got = append(got, k)
+		return len(got) < 2
+	})
+	b.TopMax(10, func(k int, v string) bool {
+		got = append(got, k)
+		return len(got) < 3
+	})
+	if want := []int{0, 1, 99}; !equalSlices(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func equalSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 太大的元素只拒绝这一次写入, 之前和之后没有Sync的写入都不会丢
+func Test_DiskBtree_TooLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b := openTest(t, path)
+	want := make(map[int]string)
+	for i := 0; i < 100; i++ {
+		b.Set(i, "a")
+		want[i] = "a"
+	}
+
+	big := strings.Repeat("x", 256)
+	if err := b.Put(1, big); err != ErrTooLarge {
+		t.Fatalf("err %v, want %v", err, ErrTooLarge)
+	}
+	if _, replaced := b.Swap(2, big); replaced {
+		t.Fatal("too large item replaced")
+	}
+	b.Set(3, big)
+	if b.Err() != nil || b.Get(1) != "a" {
+		t.Fatalf("err %v, get %q", b.Err(), b.Get(1))
+	}
+
+	for i := 100; i < 200; i++ {
+		if err := b.Put(i, "b"); err != nil {
+			t.Fatal(err)
+		}
+		want[i] = "b"
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b = openTest(t, path)
+	defer b.Close()
+	checkTree(t, b, want)
+}
+
+// 写两批数据, 第一批Sync, 第二批只生成wal记录, 然后模拟崩溃
+func crash(t *testing.T, path string, write func(b *DiskBtree[int, string], rec []byte)) (synced, pending map[int]string) {
+	b := openTest(t, path)
+	synced = make(map[int]string)
+	for i := 0; i < 200; i++ {
+		b.Set(i, "old")
+		synced[i] = "old"
+	}
+	if err := b.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	pending = make(map[int]string)
+	for k, v := range synced {
+		pending[k] = v
+	}
+	for i := 100; i < 400; i++ {
+		b.Set(i, "new")
+		pending[i] = "new"
+	}
+	for i := 0; i < 50; i++ {
+		b.Delete(i)
+		delete(pending, i)
+	}
+
+	rec, _ := b.p.walRecord()
+	write(b, rec)
+	// 不Sync直接关闭文件, 相当于进程崩溃
+	if err := b.p.close(); err != nil {
+		t.Fatal(err)
+	}
+	return synced, pending
+}
+
+func Test_DiskBtree_TornWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	synced, _ := crash(t, path, func(b *DiskBtree[int, string], rec []byte) {
+		if _, err := b.p.wal.WriteAt(rec[:len(rec)/2], 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	b := openTest(t, path)
+	checkTree(t, b, synced)
+	if st, _ := os.Stat(path + ".wal"); st.Size() != 0 {
+		t.Fatalf("wal not truncated, size %d", st.Size())
+	}
+	b.Close()
+}
+
+func Test_DiskBtree_ReplayWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	_, pending := crash(t, path, func(b *DiskBtree[int, string], rec []byte) {
+		if _, err := b.p.wal.WriteAt(rec, 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	b := openTest(t, path)
+	checkTree(t, b, pending)
+	b.Close()
+}
+
+func Test_DiskBtree_TornPageWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	_, pending := crash(t, path, func(b *DiskBtree[int, string], rec []byte) {
+		if _, err := b.p.wal.WriteAt(rec, 0); err != nil {
+			t.Fatal(err)
+		}
+		// 写回数据文件写到一半: meta和部分页被写坏
+		garbage := make([]byte, b.p.pageSize()*3)
+		for i := range garbage {
+			garbage[i] = 0xff
+		}
+		if _, err := b.p.file.WriteAt(garbage[:len(garbage)-100], 0); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	b := openTest(t, path)
+	checkTree(t, b, pending)
+	b.Close()
+}
+
+func Test_DiskBtree_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b := openTest(t, path)
+	b.Set(1, "a")
+	b.Close()
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteAt([]byte{0}, 12)
+	f.Close()
+
+	if _, err := Open[int, string](path, nil); err != ErrCorrupt {
+		t.Fatalf("err %v, want %v", err, ErrCorrupt)
+	}
+}
+
+func Test_DiskBtree_Codec(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	path := filepath.Join(t.TempDir(), "data")
+	b, err := Open[string, point](path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Set("a", point{1, 2})
+	b.Set("b", point{3, 4})
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = Open[string, point](path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if v := b.Get("b"); v != (point{3, 4}) {
+		t.Fatalf("got %v", v)
+	}
+	if b.p.pageSize() != defaultPageSize {
+		t.Fatalf("page size %d", b.p.pageSize())
+	}
+}
+
+// 让写回数据文件失败, wal已经fsync但是没有apply
+func failApply(t *testing.T, b *DiskBtree[int, string], path string) {
+	rw := b.p.file
+	ro, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.p.file = ro
+	if err := b.p.sync(); err == nil {
+		t.Fatal("sync should fail on a read-only data file")
+	}
+	b.p.file = rw
+	ro.Close()
+}
+
+// wal里残留了比新记录更长的数据, 新记录也要能被重放
+func Test_DiskBtree_StaleWALTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b := openTest(t, path)
+	want := make(map[int]string)
+	for i := 0; i < 100; i++ {
+		b.Set(i, "v")
+		want[i] = "v"
+	}
+
+	junk := make([]byte, 1<<16)
+	if _, err := b.p.wal.WriteAt(junk, 0); err != nil {
+		t.Fatal(err)
+	}
+	failApply(t, b, path)
+	if err := b.p.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b = openTest(t, path)
+	checkTree(t, b, want)
+	b.Close()
+}
+
+// 写回失败之后再次Sync, 先重放老的wal, 再写新的记录
+func Test_DiskBtree_SyncAfterFailedApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b := openTest(t, path)
+	want := make(map[int]string)
+	for i := 0; i < 300; i++ {
+		b.Set(i, "old")
+		want[i] = "old"
+	}
+	failApply(t, b, path)
+	if !b.p.walPending {
+		t.Fatal("wal should be pending")
+	}
+
+	// 新记录比老的短
+	b.Set(1, "new")
+	want[1] = "new"
+	if err := b.p.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := os.Stat(path + ".wal"); st.Size() != 0 {
+		t.Fatalf("wal not truncated, size %d", st.Size())
+	}
+	if err := b.p.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b = openTest(t, path)
+	checkTree(t, b, want)
+	b.Close()
+}
+
+// 设置了DirtyLimit, 脏页不会无限增长, 自动Sync的数据崩溃之后还在
+func Test_DiskBtree_DirtyLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	b, err := Open(path, &Options[int, string]{PageSize: 256, CacheSize: 4, DirtyLimit: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		b.Set(i, "v")
+		if len(b.p.dirty) >= 8 {
+			t.Fatalf("dirty pages %d, limit 8", len(b.p.dirty))
+		}
+	}
+	if b.Err() != nil {
+		t.Fatal(b.Err())
+	}
+	synced := b.p.synced.count
+	if synced == 0 {
+		t.Fatal("should have synced automatically")
+	}
+	// 不Sync直接关闭文件, 相当于进程崩溃
+	if err := b.p.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b = openTest(t, path)
+	if uint64(b.Len()) != synced {
+		t.Fatalf("len %d, want %d", b.Len(), synced)
+	}
+	b.Close()
+}
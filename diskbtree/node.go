@@ -0,0 +1,152 @@
+package diskbtree
+
+// apache 2.0 antlabs
+import (
+	"encoding/binary"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// 页的类型
+const (
+	kindLeaf     byte = 1
+	kindInternal byte = 2
+	kindFree     byte = 3 // 空闲页, 串成一个链表
+)
+
+// 页头: 类型(1字节) + 元素个数(2字节)
+const nodeHeader = 3
+
+// 元素, enc是key和value编码之后的内容: uvarint(len(key)) key uvarint(len(val)) val
+type item[K constraints.Ordered, V any] struct {
+	key K
+	val V
+	enc []byte
+}
+
+// 解码之后的页
+// 内部节点: children[i]里面的key都在items[i-1]和items[i]之间, len(children) == len(items)+1
+type node[K constraints.Ordered, V any] struct {
+	id       uint32
+	kind     byte
+	items    []item[K, V]
+	children []uint32
+	next     uint32 // 空闲页才有, 指向下一个空闲页
+}
+
+func (n *node[K, V]) leaf() bool {
+	return n.kind == kindLeaf
+}
+
+// 编码之后的大小
+func (n *node[K, V]) size() int {
+	size := nodeHeader + 4*len(n.children)
+	for _, it := range n.items {
+		size += len(it.enc)
+	}
+	return size
+}
+
+// 返回第一个大于等于k的位置
+func (n *node[K, V]) find(k K) (index int, found bool) {
+	index = sort.Search(len(n.items), func(i int) bool { return k <= n.items[i].key })
+	return index, index < len(n.items) && n.items[index].key == k
+}
+
+// 编码到page里面, page的长度就是页大小
+func (n *node[K, V]) encode(page []byte) {
+	clear(page)
+	page[0] = n.kind
+	if n.kind == kindFree {
+		binary.LittleEndian.PutUint32(page[1:], n.next)
+		return
+	}
+
+	binary.LittleEndian.PutUint16(page[1:], uint16(len(n.items)))
+	off := nodeHeader
+	for _, c := range n.children {
+		binary.LittleEndian.PutUint32(page[off:], c)
+		off += 4
+	}
+	for _, it := range n.items {
+		off += copy(page[off:], it.enc)
+	}
+}
+
+// 编码一个元素
+func encodeItem[K constraints.Ordered, V any](kc Codec[K], vc Codec[V], k K, v V) ([]byte, error) {
+	kb, err := kc.Encode(nil, k)
+	if err != nil {
+		return nil, err
+	}
+	vb, err := vc.Encode(nil, v)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := make([]byte, 0, len(kb)+len(vb)+2*binary.MaxVarintLen32)
+	enc = binary.AppendUvarint(enc, uint64(len(kb)))
+	enc = append(enc, kb...)
+	enc = binary.AppendUvarint(enc, uint64(len(vb)))
+	return append(enc, vb...), nil
+}
+
+// 读出一段uvarint(len) data
+func readChunk(b []byte) (chunk, rest []byte, err error) {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < l {
+		return nil, nil, ErrCorrupt
+	}
+	return b[n : n+int(l)], b[n+int(l):], nil
+}
+
+// 从page解码
+func decodeNode[K constraints.Ordered, V any](kc Codec[K], vc Codec[V], id uint32, page []byte) (*node[K, V], error) {
+	n := &node[K, V]{id: id, kind: page[0]}
+	switch n.kind {
+	case kindFree:
+		n.next = binary.LittleEndian.Uint32(page[1:])
+		return n, nil
+	case kindLeaf, kindInternal:
+	default:
+		return nil, ErrCorrupt
+	}
+
+	count := int(binary.LittleEndian.Uint16(page[1:]))
+	b := page[nodeHeader:]
+	if n.kind == kindInternal {
+		if len(b) < 4*(count+1) {
+			return nil, ErrCorrupt
+		}
+		n.children = make([]uint32, count+1)
+		for i := range n.children {
+			n.children[i] = binary.LittleEndian.Uint32(b[4*i:])
+		}
+		b = b[4*(count+1):]
+	}
+
+	n.items = make([]item[K, V], count)
+	for i := range n.items {
+		start := b
+		kb, rest, err := readChunk(b)
+		if err != nil {
+			return nil, err
+		}
+		vb, rest, err := readChunk(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		it := &n.items[i]
+		if it.key, err = kc.Decode(kb); err != nil {
+			return nil, err
+		}
+		if it.val, err = vc.Decode(vb); err != nil {
+			return nil, err
+		}
+		it.enc = append([]byte(nil), start[:len(start)-len(rest)]...)
+		b = rest
+	}
+	return n, nil
+}
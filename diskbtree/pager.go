@@ -0,0 +1,354 @@
+package diskbtree
+
+// apache 2.0 antlabs
+import (
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+const (
+	metaMagic = "GSTLBTR1"
+	walMagic  = "GSTLWAL1"
+	metaSize  = 36 // magic(8) pageSize(4) root(4) pageCount(4) freeHead(4) count(8) crc(4)
+	walHeader = 16 // magic(8) pageSize(4) nPages(4)
+)
+
+// 第0页存放的元信息
+type meta struct {
+	pageSize  uint32
+	root      uint32 // 0表示空树
+	pageCount uint32 // 包含第0页
+	freeHead  uint32 // 空闲页链表, 0表示没有
+	count     uint64
+}
+
+func (m *meta) encode(page []byte) {
+	clear(page)
+	copy(page, metaMagic)
+	binary.LittleEndian.PutUint32(page[8:], m.pageSize)
+	binary.LittleEndian.PutUint32(page[12:], m.root)
+	binary.LittleEndian.PutUint32(page[16:], m.pageCount)
+	binary.LittleEndian.PutUint32(page[20:], m.freeHead)
+	binary.LittleEndian.PutUint64(page[24:], m.count)
+	binary.LittleEndian.PutUint32(page[32:], crc32.ChecksumIEEE(page[:32]))
+}
+
+func (m *meta) decode(b []byte) error {
+	if len(b) < metaSize || string(b[:8]) != metaMagic {
+		return ErrCorrupt
+	}
+	if binary.LittleEndian.Uint32(b[32:]) != crc32.ChecksumIEEE(b[:32]) {
+		return ErrCorrupt
+	}
+	m.pageSize = binary.LittleEndian.Uint32(b[8:])
+	m.root = binary.LittleEndian.Uint32(b[12:])
+	m.pageCount = binary.LittleEndian.Uint32(b[16:])
+	m.freeHead = binary.LittleEndian.Uint32(b[20:])
+	m.count = binary.LittleEndian.Uint64(b[24:])
+	if m.pageSize < minPageSize || m.pageSize > maxPageSize || m.pageCount == 0 {
+		return ErrCorrupt
+	}
+	return nil
+}
+
+// 页管理
+// 干净的页放在lru里面, 超过cacheSize就淘汰最久没用的
+// 修改过的页放在dirty里面, Sync之前不会淘汰
+type pager[K constraints.Ordered, V any] struct {
+	file      *os.File
+	wal       *os.File
+	kc        Codec[K]
+	vc        Codec[V]
+	cacheSize int
+	// 脏页超过这个数量时自动Sync, 0表示不限制
+	dirtyLimit int
+	lru        *list.List // 元素是*node[K, V], 最近使用的在前面
+	clean      map[uint32]*list.Element
+	dirty      map[uint32]*node[K, V]
+	meta       meta
+	synced     meta // 最后一次落盘的meta
+	// wal已经fsync, 但是还没有完整的写回数据文件
+	// 这时候数据文件可能只写了一部分, 覆盖wal之前必须先重放完
+	walPending bool
+}
+
+func (p *pager[K, V]) pageSize() int {
+	return int(p.meta.pageSize)
+}
+
+// 打开文件, 如果有完整的wal先重放
+func openPager[K constraints.Ordered, V any](path string, opts *Options[K, V]) (_ *pager[K, V], err error) {
+	p := &pager[K, V]{
+		kc:         opts.KeyCodec,
+		vc:         opts.ValueCodec,
+		cacheSize:  opts.CacheSize,
+		dirtyLimit: opts.DirtyLimit,
+		lru:        list.New(),
+		clean:      make(map[uint32]*list.Element),
+		dirty:      make(map[uint32]*node[K, V]),
+	}
+
+	if p.file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		return nil, err
+	}
+	if p.wal, err = os.OpenFile(path+".wal", os.O_RDWR|os.O_CREATE, 0o644); err != nil {
+		p.file.Close()
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			p.close()
+		}
+	}()
+
+	if err = p.recover(); err != nil {
+		return nil, err
+	}
+
+	st, err := p.file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if st.Size() == 0 {
+		p.meta = meta{pageSize: uint32(opts.PageSize), pageCount: 1}
+		return p, p.sync()
+	}
+
+	var head [metaSize]byte
+	if _, err = p.file.ReadAt(head[:], 0); err != nil {
+		if err == io.EOF {
+			err = ErrCorrupt
+		}
+		return nil, err
+	}
+	if err = p.meta.decode(head[:]); err != nil {
+		return nil, err
+	}
+	p.synced = p.meta
+	return p, nil
+}
+
+// 重放wal, 不完整或者校验失败的wal说明上次Sync没有成功, 直接丢弃
+func (p *pager[K, V]) recover() error {
+	if _, err := p.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	b, err := io.ReadAll(p.wal)
+	if err != nil {
+		return err
+	}
+
+	if pageSize, pages, ok := parseWAL(b); ok {
+		if err = p.apply(b, pageSize, pages); err != nil {
+			return err
+		}
+	}
+
+	if len(b) == 0 {
+		return nil
+	}
+	if err = p.wal.Truncate(0); err != nil {
+		return err
+	}
+	return p.wal.Sync()
+}
+
+// 把wal里面的页写回数据文件
+func (p *pager[K, V]) apply(b []byte, pageSize, pages int) error {
+	for i := 0; i < pages; i++ {
+		rec := b[walHeader+i*(4+pageSize):]
+		id := binary.LittleEndian.Uint32(rec)
+		if _, err := p.file.WriteAt(rec[4:4+pageSize], int64(id)*int64(pageSize)); err != nil {
+			return err
+		}
+	}
+	return p.file.Sync()
+}
+
+// 检查wal是否完整
+func parseWAL(b []byte) (pageSize, pages int, ok bool) {
+	if len(b) < walHeader+4 || !bytes.Equal(b[:8], []byte(walMagic)) {
+		return 0, 0, false
+	}
+	pageSize = int(binary.LittleEndian.Uint32(b[8:]))
+	pages = int(binary.LittleEndian.Uint32(b[12:]))
+	if pageSize < minPageSize || pageSize > maxPageSize {
+		return 0, 0, false
+	}
+	if len(b) != walHeader+pages*(4+pageSize)+4 {
+		return 0, 0, false
+	}
+	body := b[:len(b)-4]
+	if binary.LittleEndian.Uint32(b[len(body):]) != crc32.ChecksumIEEE(body) {
+		return 0, 0, false
+	}
+	return pageSize, pages, true
+}
+
+// 读取一页
+func (p *pager[K, V]) get(id uint32) (*node[K, V], error) {
+	if id == 0 || id >= p.meta.pageCount {
+		return nil, ErrCorrupt
+	}
+	if n, ok := p.dirty[id]; ok {
+		return n, nil
+	}
+	if e, ok := p.clean[id]; ok {
+		p.lru.MoveToFront(e)
+		return e.Value.(*node[K, V]), nil
+	}
+
+	page := make([]byte, p.pageSize())
+	if _, err := p.file.ReadAt(page, int64(id)*int64(p.pageSize())); err != nil {
+		if err == io.EOF {
+			err = ErrCorrupt
+		}
+		return nil, err
+	}
+	n, err := decodeNode(p.kc, p.vc, id, page)
+	if err != nil {
+		return nil, err
+	}
+	p.clean[id] = p.lru.PushFront(n)
+	return n, nil
+}
+
+// 标记成脏页, 修改节点之前调用
+func (p *pager[K, V]) markDirty(n *node[K, V]) {
+	if e, ok := p.clean[n.id]; ok {
+		p.lru.Remove(e)
+		delete(p.clean, n.id)
+	}
+	p.dirty[n.id] = n
+}
+
+// 分配一个新页, 优先使用空闲页
+func (p *pager[K, V]) alloc(kind byte) (*node[K, V], error) {
+	if p.meta.freeHead == 0 {
+		n := &node[K, V]{id: p.meta.pageCount, kind: kind}
+		p.meta.pageCount++
+		p.dirty[n.id] = n
+		return n, nil
+	}
+
+	n, err := p.get(p.meta.freeHead)
+	if err != nil {
+		return nil, err
+	}
+	if n.kind != kindFree {
+		return nil, ErrCorrupt
+	}
+	p.markDirty(n)
+	p.meta.freeHead = n.next
+	*n = node[K, V]{id: n.id, kind: kind}
+	return n, nil
+}
+
+// 释放一页, 放到空闲页链表的头部
+func (p *pager[K, V]) free(n *node[K, V]) {
+	p.markDirty(n)
+	*n = node[K, V]{id: n.id, kind: kindFree, next: p.meta.freeHead}
+	p.meta.freeHead = n.id
+}
+
+// 淘汰多余的干净页
+func (p *pager[K, V]) evict() {
+	for p.lru.Len() > p.cacheSize {
+		e := p.lru.Back()
+		p.lru.Remove(e)
+		delete(p.clean, e.Value.(*node[K, V]).id)
+	}
+}
+
+// 把所有脏页和meta编码成一条wal记录
+func (p *pager[K, V]) walRecord() (buf []byte, ids []uint32) {
+	ids = make([]uint32, 0, len(p.dirty))
+	for id := range p.dirty {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	pageSize := p.pageSize()
+	buf = make([]byte, walHeader+(len(ids)+1)*(4+pageSize)+4)
+	copy(buf, walMagic)
+	binary.LittleEndian.PutUint32(buf[8:], uint32(pageSize))
+	binary.LittleEndian.PutUint32(buf[12:], uint32(len(ids)+1))
+
+	off := walHeader
+	off += 4 // meta是第0页
+	p.meta.encode(buf[off : off+pageSize])
+	off += pageSize
+	for _, id := range ids {
+		binary.LittleEndian.PutUint32(buf[off:], id)
+		off += 4
+		p.dirty[id].encode(buf[off : off+pageSize])
+		off += pageSize
+	}
+	binary.LittleEndian.PutUint32(buf[off:], crc32.ChecksumIEEE(buf[:off]))
+	return buf, ids
+}
+
+// 落盘
+// 先把所有脏页和meta写到wal并fsync, 再写回数据文件并fsync, 最后清空wal
+// 任何一步崩溃, 下次打开时要么丢弃不完整的wal(数据文件还是上次Sync的状态), 要么重放完整的wal
+func (p *pager[K, V]) sync() error {
+	if len(p.dirty) == 0 && p.meta == p.synced {
+		return nil
+	}
+
+	// 上次写回数据文件失败了, 先用老的wal把数据文件恢复到一致的状态
+	if p.walPending {
+		if err := p.recover(); err != nil {
+			return err
+		}
+		p.walPending = false
+	}
+
+	// 先截断, 老的wal比新记录长的话, 残留的尾巴会让恢复时的长度校验失败
+	buf, ids := p.walRecord()
+	if err := p.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := p.wal.WriteAt(buf, 0); err != nil {
+		return err
+	}
+	if err := p.wal.Sync(); err != nil {
+		return err
+	}
+
+	p.walPending = true
+	if err := p.apply(buf, p.pageSize(), len(ids)+1); err != nil {
+		return err
+	}
+	if err := p.wal.Truncate(0); err != nil {
+		return err
+	}
+	if err := p.wal.Sync(); err != nil {
+		return err
+	}
+	p.walPending = false
+
+	for _, id := range ids {
+		p.clean[id] = p.lru.PushFront(p.dirty[id])
+		delete(p.dirty, id)
+	}
+	p.synced = p.meta
+	p.evict()
+	return nil
+}
+
+func (p *pager[K, V]) close() error {
+	err := p.wal.Close()
+	if err2 := p.file.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
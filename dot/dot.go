@@ -0,0 +1,11 @@
+// 各个容器WriteDOT的选项, 输出graphviz dot格式
+package dot
+
+// apache 2.0 antlabs
+
+// 输出graphviz dot格式的选项, nil等同于零值
+type Options[K any, V any] struct {
+	FormatKey   func(k K) string // 默认使用fmt.Sprint
+	FormatValue func(v V) string // 为nil时不输出value
+	MaxDepth    int              // 最多输出多少层, 0表示不限制, 超过的部分用...代替
+}
@@ -0,0 +1,211 @@
+package hasher
+
+// apache 2.0 antlabs
+
+// hash表使用的hash函数
+// 相等的key必须得到相同的hash值, 所以不能直接把key的内存当成字节处理:
+// string, 指针, interface只比较指向的内容, 结构体有填充字节, 浮点数+0和-0相等
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"reflect"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// 计算key的hash值
+type Hasher[K comparable] interface {
+	Hash(key K) uint64
+}
+
+// 函数适配成Hasher
+type Func[K comparable] func(key K) uint64
+
+func (f Func[K]) Hash(key K) uint64 {
+	return f(key)
+}
+
+// 字符串
+type String[K ~string] struct {
+	Seed uint64
+}
+
+func (s String[K]) Hash(key K) uint64 {
+	return Sum64String(string(key), s.Seed)
+}
+
+// 整数
+type Int[K constraints.Integer] struct {
+	Seed uint64
+}
+
+func (i Int[K]) Hash(key K) uint64 {
+	return Sum64Uint64(uint64(key), i.Seed)
+}
+
+// 浮点数, +0和-0相等
+type Float[K constraints.Float] struct {
+	Seed uint64
+}
+
+func (f Float[K]) Hash(key K) uint64 {
+	if key == 0 {
+		key = 0
+	}
+	return Sum64Uint64(math.Float64bits(float64(key)), f.Seed)
+}
+
+// 直接hash key的内存, 只有memHashable的类型才能使用
+// string, 指针指向的内容, 填充字节都不能这样处理, 所以不导出, 由New按类型选择
+type mem[K comparable] struct {
+	seed uint64
+}
+
+func (m mem[K]) Hash(key K) uint64 {
+	return Sum64(unsafe.Slice((*byte)(unsafe.Pointer(&key)), unsafe.Sizeof(key)), m.seed)
+}
+
+// 数组的快速实现, 直接hash数组的内存, 不分配内存, 比如[16]byte, [4]uint32
+// K必须是元素可以按内存比较的数组, 否则panic, 所以不会误用到有填充字节或者string的类型上
+// New遇到这些数组会自动选择同样的实现
+func NewArray[K comparable](seed uint64) Hasher[K] {
+	var k K
+	t := reflect.TypeOf(&k).Elem()
+	if t.Kind() != reflect.Array || !memHashable(t) {
+		panic("hasher: NewArray needs an array of integers, got " + t.String())
+	}
+	return mem[K]{seed: seed}
+}
+
+// 通用的实现, 通过反射按字段编码之后再hash, 任何comparable的类型都可以使用
+// 每次调用都要经过反射, key会逃逸到堆上, 编码超过64字节时还会再分配, 比其他实现慢很多
+// New只在没有更快的实现时才会选它
+type Reflect[K comparable] struct {
+	Seed uint64
+}
+
+func (r Reflect[K]) Hash(key K) uint64 {
+	var buf [64]byte
+	return Sum64(appendValue(buf[:0], reflect.ValueOf(&key).Elem()), r.Seed)
+}
+
+// 随机的seed, 每个hash表使用不同的seed, 防止构造大量冲突的key攻击
+func RandomSeed() uint64 {
+	return rand.Uint64()
+}
+
+// 按类型选择Hasher, seed为0
+func Default[K comparable]() Hasher[K] {
+	return New[K](0)
+}
+
+// 按类型选择带seed的Hasher
+func New[K comparable](seed uint64) Hasher[K] {
+	var k K
+	t := reflect.TypeOf(&k).Elem()
+	switch {
+	case t.Kind() == reflect.String:
+		return Func[K](func(key K) uint64 {
+			return Sum64String(*(*string)(unsafe.Pointer(&key)), seed)
+		})
+	case t.Kind() == reflect.Float32:
+		f := Float[float32]{Seed: seed}
+		return Func[K](func(key K) uint64 {
+			return f.Hash(*(*float32)(unsafe.Pointer(&key)))
+		})
+	case t.Kind() == reflect.Float64:
+		f := Float[float64]{Seed: seed}
+		return Func[K](func(key K) uint64 {
+			return f.Hash(*(*float64)(unsafe.Pointer(&key)))
+		})
+	case t.Size() == 8 && memHashable(t):
+		return Func[K](func(key K) uint64 {
+			return Sum64Uint64(*(*uint64)(unsafe.Pointer(&key)), seed)
+		})
+	case memHashable(t):
+		return mem[K]{seed: seed}
+	}
+	return Reflect[K]{Seed: seed}
+}
+
+// 相等等价于内存相等的类型
+func memHashable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return memHashable(t.Elem())
+	case reflect.Struct:
+		off := uintptr(0)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			// 有填充字节, 或者是不参与比较的_字段
+			if f.Offset != off || f.Name == "_" || !memHashable(f.Type) {
+				return false
+			}
+			off += f.Type.Size()
+		}
+		return off == t.Size()
+	}
+	return false
+}
+
+// 把v按照==的语义编码
+func appendValue(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.LittleEndian.AppendUint64(b, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.LittleEndian.AppendUint64(b, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendFloat(b, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return appendFloat(appendFloat(b, real(c)), imag(c))
+	case reflect.String:
+		// 带上长度, 避免{"ab", "c"}和{"a", "bc"}编码相同
+		b = binary.AppendUvarint(b, uint64(v.Len()))
+		return append(b, v.String()...)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return binary.LittleEndian.AppendUint64(b, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(b, 0)
+		}
+		e := v.Elem()
+		b = append(b, 1)
+		b = append(b, e.Type().String()...)
+		return appendValue(b, e)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			b = appendValue(b, v.Index(i))
+		}
+		return b
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).Name == "_" {
+				continue
+			}
+			b = appendValue(b, v.Field(i))
+		}
+		return b
+	}
+	panic("hasher: unhashable type " + v.Type().String())
+}
+
+func appendFloat(b []byte, f float64) []byte {
+	if f == 0 {
+		f = 0
+	}
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+}
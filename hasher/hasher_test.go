@@ -0,0 +1,182 @@
+package hasher
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type user struct {
+	Name string
+	Age  int8 // 后面有填充字节
+	ID   int64
+}
+
+type inner struct {
+	A any
+	P *int
+}
+
+type nested struct {
+	U   user
+	In  inner
+	Arr [2]string
+	_   int32
+	F   float64
+}
+
+type packed struct {
+	A int32
+	B uint32
+	C [8]byte
+}
+
+// 运行时拼接, 保证两个字符串的内存不同
+func dup(s string) string {
+	return strings.Clone(s)
+}
+
+func Test_Default_Struct(t *testing.T) {
+	h := Default[user]()
+	if _, ok := h.(Reflect[user]); !ok {
+		t.Fatalf("user hasher %T", h)
+	}
+
+	a := user{Name: dup("hello"), Age: 1, ID: 2}
+	b := user{Name: dup("hello"), Age: 1, ID: 2}
+	if h.Hash(a) != h.Hash(b) {
+		t.Fatal("equal keys hash differently")
+	}
+	if h.Hash(a) == h.Hash(user{Name: "hellp", Age: 1, ID: 2}) {
+		t.Fatal("different keys hash equal")
+	}
+}
+
+func Test_Default_Nested(t *testing.T) {
+	x := 1
+	a := nested{
+		U:   user{Name: dup("a")},
+		In:  inner{A: dup("x"), P: &x},
+		Arr: [2]string{dup("ab"), dup("c")},
+		F:   0,
+	}
+	b := a
+	b.U.Name = dup("a")
+	b.In.A = dup("x")
+	b.Arr = [2]string{dup("ab"), dup("c")}
+	b.F = math.Copysign(0, -1)
+	if a != b {
+		t.Fatal("keys not equal")
+	}
+
+	h := Default[nested]()
+	if h.Hash(a) != h.Hash(b) {
+		t.Fatal("equal keys hash differently")
+	}
+
+	b.Arr = [2]string{"a", "bc"}
+	if h.Hash(a) == h.Hash(b) {
+		t.Fatal("different string boundaries hash equal")
+	}
+
+	var i1, i2 any = 1, int64(1)
+	ha := Default[any]()
+	if ha.Hash(i1) == ha.Hash(i2) {
+		t.Fatal("int and int64 hash equal")
+	}
+	if ha.Hash(i1) != ha.Hash(any(1)) || ha.Hash(nil) != ha.Hash(nil) {
+		t.Fatal("equal interfaces hash differently")
+	}
+}
+
+func Test_Default_Kinds(t *testing.T) {
+	if _, ok := Default[packed]().(mem[packed]); !ok {
+		t.Fatalf("packed hasher %T", Default[packed]())
+	}
+	if _, ok := Default[[16]byte]().(mem[[16]byte]); !ok {
+		t.Fatalf("[16]byte hasher %T", Default[[16]byte]())
+	}
+	if _, ok := Default[user]().(mem[user]); ok {
+		t.Fatal("padded struct uses mem")
+	}
+
+	type myString string
+	hs := Default[myString]()
+	if hs.Hash(myString(dup("abc"))) != (String[myString]{}).Hash("abc") {
+		t.Fatal("named string")
+	}
+
+	hf := Default[float64]()
+	if hf.Hash(0) != hf.Hash(math.Copysign(0, -1)) {
+		t.Fatal("+0 and -0 hash differently")
+	}
+	if Default[float32]().Hash(1.5) != (Float[float32]{}).Hash(1.5) {
+		t.Fatal("float32")
+	}
+
+	a := [16]byte{1, 2, 3}
+	if (mem[[16]byte]{}).Hash(a) != Default[[16]byte]().Hash(a) {
+		t.Fatal("byte array")
+	}
+	if Default[[16]byte]().Hash(a) == Default[[16]byte]().Hash([16]byte{1, 2, 4}) {
+		t.Fatal("byte array hash equal")
+	}
+	if (Int[int]{}).Hash(1) == (Int[int]{}).Hash(2) {
+		t.Fatal("int")
+	}
+}
+
+func Test_MemHashable(t *testing.T) {
+	for _, tc := range []struct {
+		v    any
+		want bool
+	}{
+		{int8(0), true},
+		{"", false},
+		{0.0, false},
+		{[4]uint16{}, true},
+		{packed{}, true},
+		{user{}, false},
+		{struct {
+			A int32
+			_ int32
+		}{}, false},
+		{struct{ A any }{}, false},
+	} {
+		if got := memHashable(reflect.TypeOf(tc.v)); got != tc.want {
+			t.Fatalf("%T got %v, want %v", tc.v, got, tc.want)
+		}
+	}
+}
+
+// 数组的快速实现和New的结果一样, 不分配内存, 不能按内存比较的类型直接panic
+func Test_NewArray(t *testing.T) {
+	h := NewArray[[16]byte](7)
+	a := [16]byte{1, 2, 3}
+	if h.Hash(a) != New[[16]byte](7).Hash(a) || h.Hash(a) == h.Hash([16]byte{1, 2, 4}) {
+		t.Fatal("array hash")
+	}
+	if n := testing.AllocsPerRun(100, func() { h.Hash(a) }); n != 0 {
+		t.Fatalf("%v allocs per hash", n)
+	}
+	if NewArray[[4]uint32](7).Hash([4]uint32{1}) == NewArray[[4]uint32](7).Hash([4]uint32{2}) {
+		t.Fatal("uint32 array hash equal")
+	}
+
+	for name, f := range map[string]func(){
+		"int":        func() { NewArray[int](0) },
+		"[2]string":  func() { NewArray[[2]string](0) },
+		"[2]float64": func() { NewArray[[2]float64](0) },
+		"[2]user":    func() { NewArray[[2]user](0) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,55 @@
+package hasher
+
+import (
+	"encoding/binary"
+	"testing"
+
+	xxhash "github.com/cespare/xxhash/v2"
+)
+
+func Test_Sum64(t *testing.T) {
+	b := make([]byte, 100)
+	for i := range b {
+		b[i] = byte(i * 7)
+	}
+
+	for n := 0; n <= len(b); n++ {
+		if got, want := Sum64(b[:n], 0), xxhash.Sum64(b[:n]); got != want {
+			t.Fatalf("len %d got %x, want %x", n, got, want)
+		}
+		if Sum64String(string(b[:n]), 1) != Sum64(b[:n], 1) {
+			t.Fatalf("len %d string", n)
+		}
+		if Sum64(b[:n], 1) == Sum64(b[:n], 2) {
+			t.Fatalf("len %d seed not used", n)
+		}
+	}
+
+	var buf [8]byte
+	for _, v := range []uint64{0, 1, 1 << 63, 0x0123456789abcdef} {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		if Sum64Uint64(v, 3) != Sum64(buf[:], 3) {
+			t.Fatalf("uint64 %x", v)
+		}
+	}
+}
+
+func Test_Seed(t *testing.T) {
+	type key struct {
+		S string
+		N int
+	}
+	a, b := New[key](1), New[key](2)
+	if a.Hash(key{"x", 1}) == b.Hash(key{"x", 1}) {
+		t.Fatal("seed not used")
+	}
+	if a.Hash(key{"x", 1}) != New[key](1).Hash(key{dup("x"), 1}) {
+		t.Fatal("same seed hash differently")
+	}
+	if New[int](1).Hash(5) == New[int](2).Hash(5) || New[string](1).Hash("a") == New[string](2).Hash("a") {
+		t.Fatal("seed not used")
+	}
+	if RandomSeed() == RandomSeed() {
+		t.Fatal("random seed")
+	}
+}
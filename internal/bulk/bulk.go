@@ -0,0 +1,64 @@
+// 从升序数据批量构造有序容器时共用的检查, rbtree, avltree, btree, skiplist都用这一份
+package bulk
+
+// apache 2.0 antlabs
+import (
+	"errors"
+
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	ErrNotSorted    = errors.New("gstl: keys are not sorted")
+	ErrDuplicateKey = errors.New("gstl: duplicate key")
+	ErrLenMismatch  = errors.New("gstl: keys and vals have different length")
+)
+
+// 按顺序取出元素, 顺便检查是否升序, 是否有重复的key
+type Iter[K constraints.Ordered, V any] struct {
+	f    func(i int) (K, V)
+	i    int
+	prev K
+	err  error
+}
+
+// f按0到n-1的顺序调用
+func NewIter[K constraints.Ordered, V any](f func(i int) (K, V)) *Iter[K, V] {
+	return &Iter[K, V]{f: f}
+}
+
+// 把keys, vals包装成FromSortedFunc需要的函数, 长度不一样返回错误
+func Slices[K constraints.Ordered, V any](keys []K, vals []V) (func(i int) (K, V), error) {
+	if len(keys) != len(vals) {
+		return nil, ErrLenMismatch
+	}
+
+	return func(i int) (K, V) {
+		return keys[i], vals[i]
+	}, nil
+}
+
+// 取出下一个元素
+// 出错之后不会再调用f, 直接返回零值, 调用方通过Err判断是否要提前结束
+func (it *Iter[K, V]) Next() (k K, v V) {
+	if it.err != nil {
+		return
+	}
+
+	k, v = it.f(it.i)
+	if it.i > 0 {
+		if k == it.prev {
+			it.err = ErrDuplicateKey
+		} else if k < it.prev {
+			it.err = ErrNotSorted
+		}
+	}
+	it.prev = k
+	it.i++
+	return
+}
+
+// 第一个错误
+func (it *Iter[K, V]) Err() error {
+	return it.err
+}
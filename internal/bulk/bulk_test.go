@@ -0,0 +1,47 @@
+package bulk
+
+import "testing"
+
+// 出错之后不再调用f
+func Test_Iter_StopAtFirstError(t *testing.T) {
+	keys := []int{1, 2, 5, 3, 4, 6}
+	calls := 0
+	it := NewIter(func(i int) (int, int) {
+		calls++
+		return keys[i], i
+	})
+
+	for i := 0; i < len(keys); i++ {
+		it.Next()
+	}
+
+	if it.Err() != ErrNotSorted {
+		t.Fatalf("Expected %v, got %v", ErrNotSorted, it.Err())
+	}
+	if calls != 4 {
+		t.Fatalf("Expected 4 calls, got %d", calls)
+	}
+}
+
+func Test_Iter_Duplicate(t *testing.T) {
+	it := NewIter(func(i int) (int, int) { return i / 2, i })
+	it.Next()
+	it.Next()
+	if it.Err() != ErrDuplicateKey {
+		t.Fatalf("Expected %v, got %v", ErrDuplicateKey, it.Err())
+	}
+}
+
+func Test_Slices(t *testing.T) {
+	if _, err := Slices([]int{1, 2}, []int{1}); err != ErrLenMismatch {
+		t.Fatalf("Expected %v, got %v", ErrLenMismatch, err)
+	}
+
+	f, err := Slices([]int{1, 2}, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k, v := f(1); k != 2 || v != "b" {
+		t.Fatalf("Expected (2, b), got (%d, %s)", k, v)
+	}
+}
@@ -0,0 +1,95 @@
+// 各个容器WriteDOT共用的辅助函数, 对外只暴露dot.Options
+package dot
+
+// apache 2.0 antlabs
+
+// 参考资料
+// https://graphviz.org/doc/info/lang.html
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+// 转成dot里面的双引号字符串
+func Quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// 转义shape=record里面的一个字段, 结果直接放到双引号里面, 不需要再Quote
+func EscapeRecord(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\n':
+			b.WriteString(`\n`)
+			continue
+		case '\\', '"', '{', '}', '|', '<', '>', ' ':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// 包装io.Writer和选项, 只记录第一个错误, 最后调用Err检查
+type Writer[K any, V any] struct {
+	w    io.Writer
+	opts *dot.Options[K, V]
+	err  error
+	id   int
+}
+
+// opts为nil时使用默认的选项
+func NewWriter[K any, V any](w io.Writer, opts *dot.Options[K, V]) *Writer[K, V] {
+	return &Writer[K, V]{w: w, opts: opts}
+}
+
+// 写一行
+func (w *Writer[K, V]) Printf(format string, a ...any) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format+"\n", a...)
+}
+
+// 返回一个新的节点名
+func (w *Writer[K, V]) NextID() string {
+	w.id++
+	return fmt.Sprintf("n%d", w.id)
+}
+
+// 超过MaxDepth的子树用一个...节点代替, 返回节点名
+func (w *Writer[K, V]) Ellipsis() string {
+	id := w.NextID()
+	w.Printf("\t%s [label=\"...\", shape=plaintext, style=\"\", fontcolor=black];", id)
+	return id
+}
+
+// 返回第一个写错误
+func (w *Writer[K, V]) Err() error {
+	return w.err
+}
+
+// 格式化节点的内容, 设置了FormatValue时是"key: value"
+func (w *Writer[K, V]) Label(k K, v V) string {
+	o := w.opts
+	key := fmt.Sprint(k)
+	if o != nil && o.FormatKey != nil {
+		key = o.FormatKey(k)
+	}
+	if o == nil || o.FormatValue == nil {
+		return key
+	}
+	return key + ": " + o.FormatValue(v)
+}
+
+// 第depth层(从0开始)是否超过了MaxDepth
+func (w *Writer[K, V]) Truncated(depth int) bool {
+	return w.opts != nil && w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth
+}
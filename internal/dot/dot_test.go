@@ -0,0 +1,72 @@
+package dot
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+func Test_Quote(t *testing.T) {
+	for s, want := range map[string]string{
+		`abc`:   `"abc"`,
+		`a"b`:   `"a\"b"`,
+		`a\b`:   `"a\\b"`,
+		"a\nb":  `"a\nb"`,
+		`{a|b}`: `"{a|b}"`,
+	} {
+		if got := Quote(s); got != want {
+			t.Errorf("Quote(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func Test_EscapeRecord(t *testing.T) {
+	if got, want := EscapeRecord(`{a|b} <c>`), `\{a\|b\}\ \<c\>`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func Test_Options(t *testing.T) {
+	w := NewWriter[int, int](io.Discard, nil)
+	if w.Label(1, 2) != "1" || w.Truncated(100) {
+		t.Fatal("nil options should use default")
+	}
+
+	w = NewWriter(io.Discard, &dot.Options[int, int]{
+		FormatKey:   func(k int) string { return "k" + strconv.Itoa(k) },
+		FormatValue: func(v int) string { return "v" + strconv.Itoa(v) },
+		MaxDepth:    2,
+	})
+	if got := w.Label(1, 2); got != "k1: v2" {
+		t.Fatalf("got %s", got)
+	}
+	if w.Truncated(1) || !w.Truncated(2) {
+		t.Fatal("wrong Truncated")
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write error")
+}
+
+func Test_Writer(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter[int, int](&buf, nil)
+	w.Printf("a %d", 1)
+	if buf.String() != "a 1\n" || w.NextID() != "n1" || w.NextID() != "n2" || w.Err() != nil {
+		t.Fatalf("got %q", buf.String())
+	}
+
+	w = NewWriter[int, int](errWriter{}, nil)
+	w.Printf("a")
+	w.Printf("b")
+	if w.Err() == nil {
+		t.Fatal("should return write error")
+	}
+}
@@ -0,0 +1,106 @@
+// 有序容器的区间遍历和区间删除, 只依赖游标, rbtree, avltree, btree, bplustree共用这一份实现
+// avltree的区间删除用自己的split/join
+package rangeop
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+// 双向游标, 各个有序容器的Cursor都满足这个接口
+type Cursor[K constraints.Ordered, V any] interface {
+	// 定位到第一个大于等于k的元素
+	Seek(k K) bool
+	SeekFirst() bool
+	SeekLast() bool
+	Next() bool
+	Prev() bool
+	Valid() bool
+	Key() K
+	Value() V
+	// 删除当前元素, 游标移动到下一个元素
+	Delete()
+}
+
+// 没有指定区间的开闭, 默认是左闭右开[lo, hi)
+func GetBound(bound []api.Bound) api.Bound {
+	if len(bound) > 0 {
+		return bound[0]
+	}
+	return api.ClosedOpen
+}
+
+// 定位到最后一个小于等于k的元素
+func seekFloor[K constraints.Ordered, V any](c Cursor[K, V], k K) bool {
+	if !c.Seek(k) {
+		return c.SeekLast()
+	}
+
+	if c.Key() == k {
+		return true
+	}
+	return c.Prev()
+}
+
+// 定位到区间的第一个元素
+func seekLo[K constraints.Ordered, V any](c Cursor[K, V], lo K, bd api.Bound) bool {
+	ok := c.Seek(lo)
+	if ok && !bd.IncludeLo() && c.Key() == lo {
+		ok = c.Next()
+	}
+	return ok
+}
+
+// 定位到区间的最后一个元素
+func seekHi[K constraints.Ordered, V any](c Cursor[K, V], hi K, bd api.Bound) bool {
+	ok := seekFloor(c, hi)
+	if ok && !bd.IncludeHi() && c.Key() == hi {
+		ok = c.Prev()
+	}
+	return ok
+}
+
+// 升序遍历区间, 直接从lo开始, 不需要从头遍历
+func Between[K constraints.Ordered, V any](c Cursor[K, V], lo, hi K, callback func(k K, v V) bool, bd api.Bound) {
+	for ok := seekLo(c, lo, bd); ok; ok = c.Next() {
+		k := c.Key()
+		if k > hi || k == hi && !bd.IncludeHi() {
+			return
+		}
+
+		if !callback(k, c.Value()) {
+			return
+		}
+	}
+}
+
+// 降序遍历区间
+func PrevBetween[K constraints.Ordered, V any](c Cursor[K, V], lo, hi K, callback func(k K, v V) bool, bd api.Bound) {
+	for ok := seekHi(c, hi, bd); ok; ok = c.Prev() {
+		k := c.Key()
+		if k < lo || k == lo && !bd.IncludeLo() {
+			return
+		}
+
+		if !callback(k, c.Value()) {
+			return
+		}
+	}
+}
+
+// 删除区间的所有元素, 返回删除的个数
+// 每个元素走一次游标删除, 复杂度是O(k*log(n)), k是删除的个数, 不是批量删除
+// avltree有split/join, 不用这个函数
+func Delete[K constraints.Ordered, V any](c Cursor[K, V], lo, hi K, bd api.Bound) (n int) {
+	for ok := seekLo(c, lo, bd); ok; ok = c.Valid() {
+		k := c.Key()
+		if k > hi || k == hi && !bd.IncludeHi() {
+			return
+		}
+
+		c.Delete()
+		n++
+	}
+	return
+}
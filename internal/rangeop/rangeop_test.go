@@ -0,0 +1,285 @@
+package rangeop_test
+
+import (
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/avltree"
+	"github.com/antlabs/gstl/bplustree"
+	"github.com/antlabs/gstl/btree"
+	"github.com/antlabs/gstl/internal/rangeop"
+	"github.com/antlabs/gstl/rbtree"
+)
+
+type sortedTree interface {
+	Set(k, v int)
+	TryGet(k int) (int, bool)
+	Len() int
+	Range(callback func(k, v int) bool)
+	RangeBetween(lo, hi int, callback func(k, v int) bool, bound ...api.Bound)
+	RangePrevBetween(lo, hi int, callback func(k, v int) bool, bound ...api.Bound)
+	DeleteRange(lo, hi int, bound ...api.Bound) int
+}
+
+// 每种树返回树本身和一个新建游标的函数
+var trees = []struct {
+	name string
+	new  func() (sortedTree, func() rangeop.Cursor[int, int])
+}{
+	{"rbtree", func() (sortedTree, func() rangeop.Cursor[int, int]) {
+		t := rbtree.New[int, int]()
+		return t, func() rangeop.Cursor[int, int] { return t.Cursor() }
+	}},
+	{"avltree", func() (sortedTree, func() rangeop.Cursor[int, int]) {
+		t := avltree.New[int, int]()
+		return t, func() rangeop.Cursor[int, int] { return t.Cursor() }
+	}},
+	{"btree", func() (sortedTree, func() rangeop.Cursor[int, int]) {
+		t := btree.New[int, int](2)
+		return t, func() rangeop.Cursor[int, int] { return t.Cursor() }
+	}},
+	{"bplustree", func() (sortedTree, func() rangeop.Cursor[int, int]) {
+		t := bplustree.New[int, int](2)
+		return t, func() rangeop.Cursor[int, int] { return t.Cursor() }
+	}},
+}
+
+var bounds = []api.Bound{api.ClosedOpen, api.Closed, api.OpenClosed, api.Open}
+
+// 暴力计算区间内的key
+func between(keys []int, lo, hi int, bd api.Bound) (rv []int) {
+	for _, k := range keys {
+		if k < lo || k == lo && !bd.IncludeLo() {
+			continue
+		}
+		if k > hi || k == hi && !bd.IncludeHi() {
+			continue
+		}
+		rv = append(rv, k)
+	}
+	return
+}
+
+func reverse(s []int) []int {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+	return s
+}
+
+func slicesEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func keysOf(b sortedTree) (rv []int) {
+	b.Range(func(k, v int) bool {
+		rv = append(rv, k)
+		return true
+	})
+	return
+}
+
+// 游标正向和反向遍历, 结果要和Range一致
+func Test_Cursor_NextPrev(t *testing.T) {
+	for _, tr := range trees {
+		for _, max := range []int{0, 1, 10, 1000} {
+			b, cursor := tr.new()
+			for i := 0; i < max; i++ {
+				b.Set(i, i)
+			}
+
+			need := keysOf(b)
+			var got []int
+			c := cursor()
+			for ok := c.SeekFirst(); ok; ok = c.Next() {
+				got = append(got, c.Key())
+			}
+			if !slicesEqual(got, need) {
+				t.Errorf("%s: Expected %v, got %v", tr.name, need, got)
+			}
+
+			got = got[:0]
+			for ok := c.SeekLast(); ok; ok = c.Prev() {
+				got = append(got, c.Value())
+			}
+			if need = reverse(need); !slicesEqual(got, need) {
+				t.Errorf("%s: Expected %v, got %v", tr.name, need, got)
+			}
+		}
+	}
+}
+
+// Seek定位到第一个大于等于k的元素
+func Test_Cursor_Seek(t *testing.T) {
+	for _, tr := range trees {
+		b, cursor := tr.new()
+		for i := 0; i < 1000; i += 2 {
+			b.Set(i, i)
+		}
+
+		c := cursor()
+		for i := -1; i < 999; i++ {
+			if !c.Seek(i) {
+				t.Fatalf("%s: Expected true, got false for key %d", tr.name, i)
+			}
+
+			need := i
+			if need%2 != 0 {
+				need++
+			}
+			if c.Key() != need {
+				t.Errorf("%s: Expected %d, got %d for key %d", tr.name, need, c.Key(), i)
+			}
+
+			// 先后退再前进, 应该回到原来的位置
+			if c.Prev() {
+				if c.Key() != need-2 {
+					t.Errorf("%s: Expected %d, got %d", tr.name, need-2, c.Key())
+				}
+				c.Next()
+			} else {
+				c.Seek(i)
+			}
+			if c.Key() != need {
+				t.Errorf("%s: Expected %d, got %d", tr.name, need, c.Key())
+			}
+		}
+
+		if c.Seek(999) {
+			t.Errorf("%s: Expected false, got true", tr.name)
+		}
+		if c.Valid() {
+			t.Errorf("%s: Expected invalid cursor", tr.name)
+		}
+	}
+}
+
+// 边遍历边删除
+func Test_Cursor_Delete(t *testing.T) {
+	for _, tr := range trees {
+		b, cursor := tr.new()
+		max := 1000
+		for i := 0; i < max; i++ {
+			b.Set(i, i)
+		}
+
+		c := cursor()
+		for ok := c.SeekFirst(); ok; {
+			if c.Key()%2 == 0 {
+				c.Delete()
+				ok = c.Valid()
+				continue
+			}
+			ok = c.Next()
+		}
+
+		if b.Len() != max/2 {
+			t.Errorf("%s: Expected %d, got %d", tr.name, max/2, b.Len())
+		}
+
+		for i := 0; i < max; i++ {
+			_, ok := b.TryGet(i)
+			if ok != (i%2 != 0) {
+				t.Errorf("%s: Expected %t, got %t for key %d", tr.name, i%2 != 0, ok, i)
+			}
+		}
+	}
+}
+
+// 和暴力计算的结果对比, 覆盖四种开闭区间
+func Test_RangeBetween(t *testing.T) {
+	for _, tr := range trees {
+		b, _ := tr.new()
+		var keys []int
+		for i := 0; i < 200; i += 2 {
+			b.Set(i, i)
+			keys = append(keys, i)
+		}
+
+		for _, bd := range bounds {
+			for lo := -3; lo < 203; lo += 3 {
+				for hi := lo; hi < 203; hi += 7 {
+					var got []int
+					b.RangeBetween(lo, hi, func(k, v int) bool {
+						got = append(got, k)
+						return true
+					}, bd)
+					need := between(keys, lo, hi, bd)
+					if !slicesEqual(got, need) {
+						t.Fatalf("%s: bound:%d [%d, %d], Expected %v, got %v", tr.name, bd, lo, hi, need, got)
+					}
+
+					got = got[:0]
+					b.RangePrevBetween(lo, hi, func(k, v int) bool {
+						got = append(got, k)
+						return true
+					}, bd)
+					need = reverse(need)
+					if !slicesEqual(got, need) {
+						t.Fatalf("%s: bound:%d [%d, %d], Expected %v, got %v", tr.name, bd, lo, hi, need, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+// 回调返回false提前退出
+func Test_RangeBetween_Break(t *testing.T) {
+	for _, tr := range trees {
+		b, _ := tr.new()
+		for i := 0; i < 100; i++ {
+			b.Set(i, i)
+		}
+
+		var got []int
+		b.RangeBetween(10, 50, func(k, v int) bool {
+			got = append(got, k)
+			return len(got) < 3
+		})
+		if !slicesEqual(got, []int{10, 11, 12}) {
+			t.Errorf("%s: Expected %v, got %v", tr.name, []int{10, 11, 12}, got)
+		}
+	}
+}
+
+// 删除区间之后, 剩下的元素不变
+func Test_DeleteRange(t *testing.T) {
+	for _, tr := range trees {
+		for _, bd := range bounds {
+			b, _ := tr.new()
+			var keys []int
+			for i := 0; i < 1000; i++ {
+				b.Set(i, i)
+				keys = append(keys, i)
+			}
+
+			deleted := between(keys, 100, 900, bd)
+			n := b.DeleteRange(100, 900, bd)
+			if n != len(deleted) {
+				t.Errorf("%s: Expected %d, got %d", tr.name, len(deleted), n)
+			}
+			if b.Len() != len(keys)-n {
+				t.Errorf("%s: Expected %d, got %d", tr.name, len(keys)-n, b.Len())
+			}
+
+			var need []int
+			for _, k := range keys {
+				if len(between([]int{k}, 100, 900, bd)) == 0 {
+					need = append(need, k)
+				}
+			}
+			if got := keysOf(b); !slicesEqual(got, need) {
+				t.Errorf("%s: bound:%d, Expected %v, got %v", tr.name, bd, need, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package intervaltree
+
+// apache 2.0 antlabs
+// 参考资料
+// https://github.com/torvalds/linux/blob/master/include/linux/interval_tree_generic.h
+// 算法导论 14.3 区间树
+import (
+	"fmt"
+
+	"github.com/antlabs/gstl/rbtree"
+	"golang.org/x/exp/constraints"
+)
+
+// 区间树是在红黑树的基础上, 每个节点多记录一个max字段, 表示子树里面最大的右端点
+// max通过rbtree.AugmentTree的钩子维护, 旋转, 插入, 删除都和红黑树共用一份实现
+// 区间都是闭区间[lo, hi], 节点按lo排序, lo相同时按插入的顺序, 允许重复的区间
+
+type item[K constraints.Ordered, V any] struct {
+	hi  K
+	max K // 子树里面最大的hi
+	val V
+}
+
+func maxUpdate[K constraints.Ordered, V any](it, left, right *item[K, V]) {
+	it.max = it.hi
+	if left != nil && left.max > it.max {
+		it.max = left.max
+	}
+	if right != nil && right.max > it.max {
+		it.max = right.max
+	}
+}
+
+// 区间[lo, hi]和n是否有重叠
+func overlap[K constraints.Ordered, V any](n rbtree.Node[K, item[K, V]], lo, hi K) bool {
+	return n.Key() <= hi && lo <= n.Value().hi
+}
+
+// 区间树
+type IntervalTree[K constraints.Ordered, V any] struct {
+	tree *rbtree.AugmentTree[K, item[K, V]]
+}
+
+// 初始化函数
+func New[K constraints.Ordered, V any]() *IntervalTree[K, V] {
+	return &IntervalTree[K, V]{tree: rbtree.NewAugment[K, item[K, V]](maxUpdate[K, V])}
+}
+
+// 插入区间[lo, hi], 相同的区间也会插入新的节点
+// lo > hi时会交换两个端点
+func (t *IntervalTree[K, V]) Insert(lo, hi K, v V) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	t.tree.Insert(lo, item[K, V]{hi: hi, max: hi, val: v})
+}
+
+// 删除区间[lo, hi]里第一个让match返回true的节点, 有多个时只删除最早插入的那个
+// match为nil时删除区间[lo, hi]最早插入的那个节点
+func (t *IntervalTree[K, V]) Delete(lo, hi K, match func(v V) bool) (deleted bool) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	c := t.tree.Cursor()
+	for ok := c.Seek(lo); ok && c.Key() == lo; ok = c.Next() {
+		it := c.Value()
+		if it.hi == hi && (match == nil || match(it.val)) {
+			c.Delete()
+			return true
+		}
+	}
+	return false
+}
+
+// 返回区间的个数
+func (t *IntervalTree[K, V]) Len() int {
+	return t.tree.Len()
+}
+
+// 按lo升序遍历所有区间
+func (t *IntervalTree[K, V]) Range(callback func(lo, hi K, v V) bool) {
+	t.tree.Range(func(lo K, it item[K, V]) bool {
+		return callback(lo, it.hi, it.val)
+	})
+}
+
+func overlapping[K constraints.Ordered, V any](n rbtree.Node[K, item[K, V]], lo, hi K, callback func(lo, hi K, v V) bool) bool {
+	// 子树里最大的右端点都在lo左边, 不可能重叠
+	if !n.Valid() || n.Value().max < lo {
+		return true
+	}
+
+	if !overlapping(n.Left(), lo, hi, callback) {
+		return false
+	}
+
+	// 右子树的左端点都大于等于n.lo, 也不可能重叠
+	if n.Key() > hi {
+		return true
+	}
+
+	if it := n.Value(); it.hi >= lo {
+		if !callback(n.Key(), it.hi, it.val) {
+			return false
+		}
+	}
+
+	return overlapping(n.Right(), lo, hi, callback)
+}
+
+// 按lo升序遍历所有和[lo, hi]重叠的区间
+func (t *IntervalTree[K, V]) Overlapping(lo, hi K, callback func(lo, hi K, v V) bool) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	overlapping(t.tree.Root(), lo, hi, callback)
+}
+
+// 遍历所有包含point的区间
+func (t *IntervalTree[K, V]) Stabbing(point K, callback func(lo, hi K, v V) bool) {
+	t.Overlapping(point, point, callback)
+}
+
+// 返回任意一个和[lo, hi]重叠的区间, O(log n)
+func (t *IntervalTree[K, V]) AnyOverlap(lo, hi K) (ilo, ihi K, v V, ok bool) {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+
+	n := t.tree.Root()
+	for n.Valid() && !overlap(n, lo, hi) {
+		// 左子树的max如果大于等于lo, 左子树里要么有重叠的区间, 要么右子树也不可能有
+		if left := n.Left(); left.Valid() && left.Value().max >= lo {
+			n = left
+		} else {
+			n = n.Right()
+		}
+	}
+
+	if !n.Valid() {
+		return
+	}
+	it := n.Value()
+	return n.Key(), it.hi, it.val, true
+}
+
+// 检查每个节点的max
+func validateMax[K constraints.Ordered, V any](n rbtree.Node[K, item[K, V]]) error {
+	if !n.Valid() {
+		return nil
+	}
+
+	it := n.Value()
+	max := it.hi
+	for _, child := range []rbtree.Node[K, item[K, V]]{n.Left(), n.Right()} {
+		if !child.Valid() {
+			continue
+		}
+		if err := validateMax(child); err != nil {
+			return err
+		}
+		if child.Value().max > max {
+			max = child.Value().max
+		}
+	}
+
+	if it.max != max {
+		return fmt.Errorf("intervaltree: [%v, %v] has max %v, want %v", n.Key(), it.hi, it.max, max)
+	}
+	return nil
+}
+
+// 检查红黑树的性质和每个节点的max, 有问题返回错误
+func (t *IntervalTree[K, V]) Validate() error {
+	if err := t.tree.Validate(); err != nil {
+		return err
+	}
+	return validateMax(t.tree.Root())
+}
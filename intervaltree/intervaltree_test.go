@@ -0,0 +1,212 @@
+package intervaltree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+type interval struct {
+	lo, hi, v int
+}
+
+// 暴力实现, 用来对比结果
+type bruteForce []interval
+
+func (b bruteForce) overlapping(lo, hi int) (rv []interval) {
+	for _, i := range b {
+		if i.lo <= hi && lo <= i.hi {
+			rv = append(rv, i)
+		}
+	}
+	sortIntervals(rv)
+	return
+}
+
+func (b *bruteForce) delete(lo, hi, v int) bool {
+	for i, x := range *b {
+		if x.lo == lo && x.hi == hi && x.v == v {
+			*b = append((*b)[:i], (*b)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func sortIntervals(s []interval) {
+	sort.SliceStable(s, func(i, j int) bool {
+		if s[i].lo != s[j].lo {
+			return s[i].lo < s[j].lo
+		}
+		if s[i].hi != s[j].hi {
+			return s[i].hi < s[j].hi
+		}
+		return s[i].v < s[j].v
+	})
+}
+
+func collect(f func(cb func(lo, hi, v int) bool)) (rv []interval) {
+	f(func(lo, hi, v int) bool {
+		rv = append(rv, interval{lo, hi, v})
+		return true
+	})
+	sortIntervals(rv)
+	return
+}
+
+func equalIntervals(a, b []interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func eq[V comparable](v V) func(V) bool {
+	return func(x V) bool { return x == v }
+}
+
+// 随机插入删除, 查询结果和暴力实现对比
+func Test_IntervalTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := New[int, int]()
+	var bf bruteForce
+
+	for i := 0; i < 3000; i++ {
+		lo := r.Intn(1000)
+		hi := lo + r.Intn(50)
+
+		switch op := r.Intn(4); {
+		case op == 0 && len(bf) > 0:
+			// 删除一个存在的区间
+			x := bf[r.Intn(len(bf))]
+			if !tree.Delete(x.lo, x.hi, eq(x.v)) {
+				t.Fatalf("Delete [%d, %d] %d Expected true, got false", x.lo, x.hi, x.v)
+			}
+			bf.delete(x.lo, x.hi, x.v)
+		case op == 1:
+			// 删除一个可能不存在的区间
+			if tree.Delete(lo, hi, eq(i)) != bf.delete(lo, hi, i) {
+				t.Fatalf("Delete [%d, %d] %d not match", lo, hi, i)
+			}
+		default:
+			// 少量的值让重复区间出现得更频繁
+			v := r.Intn(3)
+			tree.Insert(lo, hi, v)
+			bf = append(bf, interval{lo, hi, v})
+			// 重复插入一份相同的区间
+			if r.Intn(10) == 0 {
+				tree.Insert(lo, hi, v+1)
+				bf = append(bf, interval{lo, hi, v + 1})
+			}
+		}
+
+		if tree.Len() != len(bf) {
+			t.Fatalf("Expected len %d, got %d", len(bf), tree.Len())
+		}
+
+		if i%100 == 0 {
+			if err := tree.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		qlo := r.Intn(1100) - 50
+		qhi := qlo + r.Intn(30)
+		need := bf.overlapping(qlo, qhi)
+		got := collect(func(cb func(lo, hi, v int) bool) { tree.Overlapping(qlo, qhi, cb) })
+		if !equalIntervals(need, got) {
+			t.Fatalf("Overlapping [%d, %d] Expected %v, got %v", qlo, qhi, need, got)
+		}
+
+		_, _, _, ok := tree.AnyOverlap(qlo, qhi)
+		if ok != (len(need) > 0) {
+			t.Fatalf("AnyOverlap [%d, %d] Expected %t, got %t", qlo, qhi, len(need) > 0, ok)
+		}
+
+		need = bf.overlapping(qlo, qlo)
+		got = collect(func(cb func(lo, hi, v int) bool) { tree.Stabbing(qlo, cb) })
+		if !equalIntervals(need, got) {
+			t.Fatalf("Stabbing %d Expected %v, got %v", qlo, need, got)
+		}
+	}
+
+	all := append(bruteForce{}, bf...)
+	sortIntervals(all)
+	if got := collect(tree.Range); !equalIntervals(all, got) {
+		t.Fatalf("Range Expected %v, got %v", all, got)
+	}
+}
+
+// AnyOverlap返回的区间一定是重叠的
+func Test_IntervalTree_AnyOverlap(t *testing.T) {
+	tree := New[int, string]()
+	tree.Insert(10, 20, "a")
+	tree.Insert(30, 40, "b")
+	tree.Insert(5, 8, "c")
+
+	lo, hi, v, ok := tree.AnyOverlap(35, 50)
+	if !ok || lo != 30 || hi != 40 || v != "b" {
+		t.Errorf("Expected [30, 40] b, got [%d, %d] %s %t", lo, hi, v, ok)
+	}
+
+	if _, _, _, ok := tree.AnyOverlap(21, 29); ok {
+		t.Errorf("Expected false, got true")
+	}
+}
+
+// 重复区间, 删除时用match区分
+func Test_IntervalTree_Duplicate(t *testing.T) {
+	tree := New[int, string]()
+	tree.Insert(1, 5, "a")
+	tree.Insert(1, 5, "b")
+	tree.Insert(5, 1, "c")
+
+	var got []string
+	tree.Stabbing(3, func(lo, hi int, v string) bool {
+		got = append(got, v)
+		return true
+	})
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Expected [a b c], got %v", got)
+	}
+
+	if !tree.Delete(1, 5, eq("b")) {
+		t.Errorf("Expected true, got false")
+	}
+	if tree.Delete(1, 5, eq("b")) {
+		t.Errorf("Expected false, got true")
+	}
+	if tree.Len() != 2 {
+		t.Errorf("Expected 2, got %d", tree.Len())
+	}
+}
+
+// match为nil时删除最早插入的那个, 值不需要是comparable
+func Test_IntervalTree_DeleteNilMatch(t *testing.T) {
+	tree := New[int, []int]()
+	tree.Insert(1, 5, []int{1})
+	tree.Insert(1, 5, []int{2})
+	tree.Insert(1, 6, []int{3})
+
+	if !tree.Delete(1, 5, nil) {
+		t.Fatalf("Expected true, got false")
+	}
+
+	var got []int
+	tree.Range(func(lo, hi int, v []int) bool {
+		got = append(got, v[0])
+		return true
+	})
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("Expected [2 3], got %v", got)
+	}
+
+	if tree.Delete(2, 5, nil) {
+		t.Errorf("Expected false, got true")
+	}
+}
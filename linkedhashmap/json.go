@@ -0,0 +1,130 @@
+package linkedhashmap
+
+// apache 2.0 antlabs
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+var ErrKeyType = errors.New("linkedhashmap: unsupported json key type")
+
+// 按顺序序列化成json object, key和encoding/json的规则一样
+// 支持string, 整数类型和实现encoding.TextMarshaler的类型
+// 值接收者, json.Marshal传值和传指针都能按顺序输出
+func (l LinkedHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	buf.WriteByte('{')
+	l.Range(func(k K, v V) bool {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		var s string
+		if s, err = encodeKey(k); err != nil {
+			return false
+		}
+		var b []byte
+		if b, err = json.Marshal(s); err != nil {
+			return false
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+
+		if b, err = json.Marshal(v); err != nil {
+			return false
+		}
+		buf.Write(b)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// 按json object里的顺序插入, 已有的数据会保留, 重复的key后面的覆盖前面的
+func (l *LinkedHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if d, ok := t.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("linkedhashmap: expect json object, got %v", t)
+	}
+
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		var k K
+		if err = decodeKey(t.(string), &k); err != nil {
+			return err
+		}
+
+		var v V
+		if err = dec.Decode(&v); err != nil {
+			return err
+		}
+		l.Set(k, v)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func encodeKey(k any) (string, error) {
+	if tm, ok := k.(encoding.TextMarshaler); ok {
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+
+	rv := reflect.ValueOf(k)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("%w: %T", ErrKeyType, k)
+}
+
+func decodeKey(s string, k any) error {
+	if tu, ok := k.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+
+	rv := reflect.ValueOf(k).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrKeyType, rv.Type())
+}
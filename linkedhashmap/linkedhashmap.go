@@ -0,0 +1,225 @@
+package linkedhashmap
+
+// apache 2.0 antlabs
+
+// 记住插入顺序的hash表, hash表里存放双向链表的节点
+// 查找, 插入, 删除都是O(1), 遍历按插入顺序
+// 访问顺序模式下, Get和Set都会把元素移到链表尾部, 链表头部就是最久没有访问的元素, 可以用来实现LRU
+import (
+	"github.com/antlabs/gstl/api"
+)
+
+var _ api.Map[int, int] = (*LinkedHashMap[int, int])(nil)
+
+type entry[K comparable, V any] struct {
+	prev, next *entry[K, V]
+	key        K
+	val        V
+}
+
+type LinkedHashMap[K comparable, V any] struct {
+	m map[K]*entry[K, V]
+	// 哨兵节点, root.next是最老的元素, root.prev是最新的元素
+	// 用指针保存, 复制LinkedHashMap的值时和map一样指向同一份数据
+	root *entry[K, V]
+	config
+}
+
+// 按插入顺序
+func New[K comparable, V any]() *LinkedHashMap[K, V] {
+	return NewWithOpt[K, V]()
+}
+
+func NewWithOpt[K comparable, V any](opts ...Option) *LinkedHashMap[K, V] {
+	var l LinkedHashMap[K, V]
+	for _, o := range opts {
+		o.apply(&l.config)
+	}
+	return l.Init()
+}
+
+// 初始化, 已有的数据会被清空
+func (l *LinkedHashMap[K, V]) Init() *LinkedHashMap[K, V] {
+	l.m = make(map[K]*entry[K, V], l.cap)
+	l.root = &entry[K, V]{}
+	l.root.next = l.root
+	l.root.prev = l.root
+	return l
+}
+
+func (l *LinkedHashMap[K, V]) lazyinit() {
+	if l.m == nil {
+		l.Init()
+	}
+}
+
+func (l *LinkedHashMap[K, V]) unlink(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// 把e插入到at后面
+func (l *LinkedHashMap[K, V]) insert(e, at *entry[K, V]) {
+	e.prev = at
+	e.next = at.next
+	at.next.prev = e
+	at.next = e
+}
+
+func (l *LinkedHashMap[K, V]) move(e, at *entry[K, V]) {
+	if e == at || e.prev == at {
+		return
+	}
+	l.unlink(e)
+	l.insert(e, at)
+}
+
+// 访问顺序模式下, 访问过的元素移到尾部
+func (l *LinkedHashMap[K, V]) access(e *entry[K, V]) {
+	if l.accessOrder {
+		l.move(e, l.root.prev)
+	}
+}
+
+// 获取
+func (l *LinkedHashMap[K, V]) Get(k K) (elem V) {
+	elem, _ = l.TryGet(k)
+	return
+}
+
+// 获取, 访问顺序模式下会修改顺序
+func (l *LinkedHashMap[K, V]) TryGet(k K) (elem V, ok bool) {
+	e, ok := l.m[k]
+	if !ok {
+		return
+	}
+	l.access(e)
+	return e.val, true
+}
+
+// 获取, 不修改顺序
+func (l *LinkedHashMap[K, V]) Peek(k K) (elem V, ok bool) {
+	e, ok := l.m[k]
+	if !ok {
+		return
+	}
+	return e.val, true
+}
+
+// 设置
+func (l *LinkedHashMap[K, V]) Set(k K, v V) {
+	l.Swap(k, v)
+}
+
+// 设置值, 已经存在的key在插入顺序模式下位置不变
+func (l *LinkedHashMap[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	l.lazyinit()
+	if e, ok := l.m[k]; ok {
+		prev, e.val = e.val, v
+		l.access(e)
+		return prev, true
+	}
+
+	e := &entry[K, V]{key: k, val: v}
+	l.insert(e, l.root.prev)
+	l.m[k] = e
+	return
+}
+
+// 删除
+func (l *LinkedHashMap[K, V]) Delete(k K) {
+	l.DeleteWithPrev(k)
+}
+
+// 删除, 返回被删除的值
+func (l *LinkedHashMap[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
+	e, ok := l.m[k]
+	if !ok {
+		return
+	}
+	l.unlink(e)
+	e.prev, e.next = nil, nil
+	delete(l.m, k)
+	return e.val, true
+}
+
+// 元素个数
+func (l *LinkedHashMap[K, V]) Len() int {
+	return len(l.m)
+}
+
+// 从老到新遍历, 回调函数里可以删除当前元素
+func (l *LinkedHashMap[K, V]) Range(callback func(k K, v V) bool) {
+	if l.m == nil {
+		return
+	}
+	for e := l.root.next; e != l.root; {
+		next := e.next
+		if !callback(e.key, e.val) {
+			return
+		}
+		e = next
+	}
+}
+
+// 从新到老遍历, 回调函数里可以删除当前元素
+func (l *LinkedHashMap[K, V]) RangeReverse(callback func(k K, v V) bool) {
+	if l.m == nil {
+		return
+	}
+	for e := l.root.prev; e != l.root; {
+		prev := e.prev
+		if !callback(e.key, e.val) {
+			return
+		}
+		e = prev
+	}
+}
+
+// 移到尾部, 变成最新的元素. key不存在返回false
+func (l *LinkedHashMap[K, V]) MoveToBack(k K) bool {
+	e, ok := l.m[k]
+	if !ok {
+		return false
+	}
+	l.move(e, l.root.prev)
+	return true
+}
+
+// 移到头部, 变成最老的元素. key不存在返回false
+func (l *LinkedHashMap[K, V]) MoveToFront(k K) bool {
+	e, ok := l.m[k]
+	if !ok {
+		return false
+	}
+	l.move(e, l.root)
+	return true
+}
+
+// 最老的元素
+func (l *LinkedHashMap[K, V]) Oldest() (k K, v V, ok bool) {
+	if l.Len() == 0 {
+		return
+	}
+	e := l.root.next
+	return e.key, e.val, true
+}
+
+// 最新的元素
+func (l *LinkedHashMap[K, V]) Newest() (k K, v V, ok bool) {
+	if l.Len() == 0 {
+		return
+	}
+	e := l.root.prev
+	return e.key, e.val, true
+}
+
+// 按顺序返回所有的key
+func (l *LinkedHashMap[K, V]) Keys() []K {
+	keys := make([]K, 0, l.Len())
+	l.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
@@ -0,0 +1,32 @@
+package linkedhashmap
+
+import "testing"
+
+func Benchmark_LinkedHashMap_Set(b *testing.B) {
+	l := New[int, int]()
+	for i := 0; i < b.N; i++ {
+		l.Set(i, i)
+	}
+}
+
+func Benchmark_LinkedHashMap_Get(b *testing.B) {
+	l := New[int, int]()
+	for i := 0; i < b.N; i++ {
+		l.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Get(i)
+	}
+}
+
+func Benchmark_LinkedHashMap_Get_AccessOrder(b *testing.B) {
+	l := NewWithOpt[int, int](WithAccessOrder())
+	for i := 0; i < b.N; i++ {
+		l.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Get(i)
+	}
+}
@@ -0,0 +1,246 @@
+package linkedhashmap
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// 检查链表和hash表是否一致, 返回按顺序的key
+func checkList(t *testing.T, l *LinkedHashMap[int, int]) []int {
+	var keys []int
+	for e := l.root.next; e != l.root; e = e.next {
+		if e.next.prev != e {
+			t.Fatalf("key %d broken link", e.key)
+		}
+		if l.m[e.key] != e {
+			t.Fatalf("key %d not in map", e.key)
+		}
+		keys = append(keys, e.key)
+	}
+	if len(keys) != l.Len() {
+		t.Fatalf("list len %d, map len %d", len(keys), l.Len())
+	}
+	return keys
+}
+
+func Test_LinkedHashMap_Order(t *testing.T) {
+	var l LinkedHashMap[int, int]
+	for _, k := range []int{3, 1, 2} {
+		l.Set(k, k*10)
+	}
+	l.Set(3, 31)
+	if keys := checkList(t, &l); !reflect.DeepEqual(keys, []int{3, 1, 2}) {
+		t.Fatalf("got %v", keys)
+	}
+	if v := l.Get(3); v != 31 {
+		t.Fatalf("got %d", v)
+	}
+
+	var rev []int
+	l.RangeReverse(func(k, v int) bool {
+		rev = append(rev, k)
+		return true
+	})
+	if !reflect.DeepEqual(rev, []int{2, 1, 3}) {
+		t.Fatalf("got %v", rev)
+	}
+
+	if !l.MoveToBack(3) || !l.MoveToFront(2) || l.MoveToBack(4) {
+		t.Fatal("move failed")
+	}
+	if keys := checkList(t, &l); !reflect.DeepEqual(keys, []int{2, 1, 3}) {
+		t.Fatalf("got %v", keys)
+	}
+	if k, v, ok := l.Oldest(); !ok || k != 2 || v != 20 {
+		t.Fatalf("oldest got (%d, %d, %v)", k, v, ok)
+	}
+	if k, v, ok := l.Newest(); !ok || k != 3 || v != 31 {
+		t.Fatalf("newest got (%d, %d, %v)", k, v, ok)
+	}
+
+	// 遍历的时候删除
+	l.Range(func(k, v int) bool {
+		l.Delete(k)
+		return true
+	})
+	if l.Len() != 0 {
+		t.Fatalf("len %d", l.Len())
+	}
+	if _, _, ok := l.Oldest(); ok {
+		t.Fatal("oldest on empty map")
+	}
+}
+
+func Test_LinkedHashMap_AccessOrder(t *testing.T) {
+	l := NewWithOpt[int, int](WithAccessOrder())
+	for i := 0; i < 5; i++ {
+		l.Set(i, i)
+	}
+	l.Get(1)
+	l.Set(0, 10)
+	l.Peek(2)
+	if keys := checkList(t, l); !reflect.DeepEqual(keys, []int{2, 3, 4, 1, 0}) {
+		t.Fatalf("got %v", keys)
+	}
+}
+
+func Test_LinkedHashMap_Random(t *testing.T) {
+	l := New[int, int]()
+	want := make(map[int]int)
+	var order []int
+	r := rand.New(rand.NewSource(1))
+
+	remove := func(k int) {
+		for i, x := range order {
+			if x == k {
+				order = append(order[:i], order[i+1:]...)
+				return
+			}
+		}
+	}
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(200)
+		switch r.Intn(4) {
+		case 0, 1:
+			prev, replaced := l.Swap(k, i)
+			old, ok := want[k]
+			if replaced != ok || prev != old {
+				t.Fatalf("swap %d got (%d, %v), want (%d, %v)", k, prev, replaced, old, ok)
+			}
+			if !ok {
+				order = append(order, k)
+			}
+			want[k] = i
+		case 2:
+			prev, deleted := l.DeleteWithPrev(k)
+			old, ok := want[k]
+			if deleted != ok || prev != old {
+				t.Fatalf("delete %d got (%d, %v), want (%d, %v)", k, prev, deleted, old, ok)
+			}
+			delete(want, k)
+			remove(k)
+		case 3:
+			if l.MoveToBack(k) {
+				remove(k)
+				order = append(order, k)
+			}
+		}
+	}
+
+	keys := checkList(t, l)
+	if !reflect.DeepEqual(keys, order) {
+		t.Fatalf("got %v, want %v", keys, order)
+	}
+	for k, v := range want {
+		if got, ok := l.TryGet(k); !ok || got != v {
+			t.Fatalf("get %d got (%d, %v), want %d", k, got, ok, v)
+		}
+	}
+}
+
+func Test_LinkedHashMap_JSON(t *testing.T) {
+	s := New[string, int]()
+	s.Set("z", 1)
+	s.Set("a", 2)
+	s.Set("m\"", 3)
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"z":1,"a":2,"m\"":3}` {
+		t.Fatalf("got %s", b)
+	}
+
+	s2 := New[string, int]()
+	if err = json.Unmarshal(b, s2); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s2.Keys(), []string{"z", "a", "m\""}) || s2.Get("a") != 2 {
+		t.Fatalf("got %v", s2.Keys())
+	}
+
+	// 作为结构体的字段, 值也是有序map
+	type config struct {
+		Ports *LinkedHashMap[uint16, *LinkedHashMap[string, bool]] `json:"ports"`
+	}
+	var c config
+	in := `{"ports":{"443":{"tls":true,"h2":false},"80":{}}}`
+	if err = json.Unmarshal([]byte(in), &c); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = json.Marshal(c); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != in {
+		t.Fatalf("got %s", b)
+	}
+
+	if err = json.Unmarshal([]byte(`{"x":1}`), New[int, int]()); err == nil {
+		t.Fatal("expect error for bad int key")
+	}
+	if _, err = json.Marshal(New[float64, int]().Init()); err != nil {
+		t.Fatal(err)
+	}
+	f := New[float64, int]()
+	f.Set(1.5, 1)
+	if _, err = json.Marshal(f); err == nil {
+		t.Fatal("expect error for float key")
+	}
+}
+
+// 复制值之后和map一样指向同一份数据, 链表不会指到别的map的哨兵上
+func Test_LinkedHashMap_Copy(t *testing.T) {
+	m := New[int, int]()
+	m.Set(1, 1)
+	m2 := *m
+	m2.Set(2, 2)
+	m.Delete(1)
+	for _, l := range []*LinkedHashMap[int, int]{m, &m2} {
+		if keys := checkList(t, l); !reflect.DeepEqual(keys, []int{2}) {
+			t.Fatalf("got %v", keys)
+		}
+	}
+
+	var zero LinkedHashMap[int, int]
+	zero.Set(3, 3)
+	if keys := checkList(t, &zero); !reflect.DeepEqual(keys, []int{3}) {
+		t.Fatalf("got %v", keys)
+	}
+}
+
+// 传值和传指针序列化的结果一样, 作为值类型的字段也能来回转换
+func Test_LinkedHashMap_JSON_Value(t *testing.T) {
+	m := New[string, int]()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	want := `{"b":1,"a":2}`
+	for _, v := range []any{m, *m} {
+		b, err := json.Marshal(v)
+		if err != nil || string(b) != want {
+			t.Fatalf("%T: got %s, err %v", v, b, err)
+		}
+	}
+
+	var zero LinkedHashMap[string, int]
+	if b, err := json.Marshal(zero); err != nil || string(b) != "{}" {
+		t.Fatalf("zero: got %s, err %v", b, err)
+	}
+
+	type config struct {
+		M LinkedHashMap[string, int] `json:"m"`
+	}
+	var c config
+	in := `{"m":{"z":1,"a":2}}`
+	if err := json.Unmarshal([]byte(in), &c); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []any{c, &c} {
+		b, err := json.Marshal(v)
+		if err != nil || string(b) != in {
+			t.Fatalf("%T: got %s, err %v", v, b, err)
+		}
+	}
+}
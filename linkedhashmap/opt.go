@@ -0,0 +1,34 @@
+package linkedhashmap
+
+// apache 2.0 antlabs
+
+type config struct {
+	cap         int
+	accessOrder bool
+}
+
+type Option interface {
+	apply(*config)
+}
+
+type withCap int
+
+func (wc withCap) apply(c *config) {
+	c.cap = int(wc)
+}
+
+// 预分配的容量
+func WithCap(cap int) Option {
+	return withCap(cap)
+}
+
+type withAccessOrder struct{}
+
+func (withAccessOrder) apply(c *config) {
+	c.accessOrder = true
+}
+
+// 按访问顺序排列, Get和Set都会把元素移到尾部
+func WithAccessOrder() Option {
+	return withAccessOrder{}
+}
@@ -0,0 +1,44 @@
+package radix
+
+// apache 2.0 antlabs
+import (
+	"io"
+	"strconv"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// 输出graphviz dot格式, 节点显示前缀, 边上显示label
+// 有值的节点画成双圈
+func (r *Radix[V]) WriteDOT(w io.Writer, opts *dot.Options[string, V]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph radix {")
+	if r.root != nil {
+		r.root.writeDOT(dw, 0)
+	}
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 返回节点名
+func (n *node[V]) writeDOT(w *idot.Writer[string, V], depth int) string {
+	if w.Truncated(depth) {
+		return w.Ellipsis()
+	}
+
+	id := w.NextID()
+	label := strconv.Quote(n.prefix)
+	shape := "circle"
+	if n.isSet {
+		label += "\n" + w.Label(n.key, n.val)
+		shape = "doublecircle"
+	}
+	w.Printf("\t%s [label=%s, shape=%s];", id, idot.Quote(label), shape)
+
+	for i := 0; i < n.edges.Len(); i++ {
+		e := n.edges.Get(i)
+		w.Printf("\t%s -> %s [label=%s];", id, e.node.writeDOT(w, depth+1), idot.Quote(string(e.label)))
+	}
+	return id
+}
@@ -0,0 +1,77 @@
+package radix
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+// 手动拼出"/", "/ab", "/ac"三个key的树
+func newDOTRadix() *Radix[int] {
+	r := &Radix[int]{length: 3}
+	r.root = &node[int]{prefix: "/", pair: pair[int]{key: "/", val: 1, isSet: true}}
+	r.root.edges.Push(edge[int]{label: 'a', node: &node[int]{prefix: "a"}})
+
+	a := r.root.edges.Get(0).node
+	a.edges.Push(edge[int]{label: 'b', node: &node[int]{prefix: "b", pair: pair[int]{key: "/ab", val: 2, isSet: true}}})
+	a.edges.Push(edge[int]{label: 'c', node: &node[int]{prefix: "c", pair: pair[int]{key: "/ac", val: 3, isSet: true}}})
+	return r
+}
+
+func Test_Radix_WriteDOT(t *testing.T) {
+	r := newDOTRadix()
+
+	var buf bytes.Buffer
+	opts := &dot.Options[string, int]{FormatValue: strconv.Itoa}
+	if err := r.WriteDOT(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	need := `digraph radix {
+	n1 [label="\"/\"\n/: 1", shape=doublecircle];
+	n2 [label="\"a\"", shape=circle];
+	n3 [label="\"b\"\n/ab: 2", shape=doublecircle];
+	n2 -> n3 [label="b"];
+	n4 [label="\"c\"\n/ac: 3", shape=doublecircle];
+	n2 -> n4 [label="c"];
+	n1 -> n2 [label="a"];
+}
+`
+	if got := buf.String(); got != need {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", need, got)
+	}
+}
+
+// 超过MaxDepth的部分用...代替
+func Test_Radix_WriteDOT_MaxDepth(t *testing.T) {
+	r := newDOTRadix()
+
+	var buf bytes.Buffer
+	if err := r.WriteDOT(&buf, &dot.Options[string, int]{MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	need := `digraph radix {
+	n1 [label="\"/\"\n/", shape=doublecircle];
+	n2 [label="...", shape=plaintext, style="", fontcolor=black];
+	n1 -> n2 [label="a"];
+}
+`
+	if got := buf.String(); got != need {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", need, got)
+	}
+}
+
+// 空树只输出图的框架
+func Test_Radix_WriteDOT_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Radix[int]{}).WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if need := "digraph radix {\n}\n"; buf.String() != need {
+		t.Fatalf("Expected %q, got %q", need, buf.String())
+	}
+}
@@ -0,0 +1,55 @@
+package rbtree
+
+// apache 2.0 antlabs
+import "golang.org/x/exp/constraints"
+
+// 根据左右孩子重新计算v上的增强数据, 孩子不存在时为nil
+type AugmentFunc[V any] func(v *V, left, right *V)
+
+// 增强红黑树, 允许重复的key, 增强数据放在V里面
+// 旋转, 插入, 删除, 替换值之后都会调用AugmentFunc, 和OrderStatTree维护size是同一套钩子
+// MultiTree的方法都可以直接使用, 查找时通过Root拿到只读的节点, 根据增强数据剪枝
+type AugmentTree[K constraints.Ordered, V any] struct {
+	MultiTree[K, V]
+}
+
+// 初始化函数
+func NewAugment[K constraints.Ordered, V any](augment AugmentFunc[V]) *AugmentTree[K, V] {
+	a := &AugmentTree[K, V]{}
+	a.root.withSize = true
+	a.root.augment = augment
+	return a
+}
+
+// 只读的节点, 零值表示空节点
+type Node[K constraints.Ordered, V any] struct {
+	n *node[K, V]
+}
+
+// 返回根节点
+func (a *AugmentTree[K, V]) Root() Node[K, V] {
+	return Node[K, V]{n: a.root.node}
+}
+
+// 节点是否存在
+func (n Node[K, V]) Valid() bool {
+	return n.n != nil
+}
+
+// 左孩子
+func (n Node[K, V]) Left() Node[K, V] {
+	return Node[K, V]{n: n.n.left}
+}
+
+// 右孩子
+func (n Node[K, V]) Right() Node[K, V] {
+	return Node[K, V]{n: n.n.right}
+}
+
+func (n Node[K, V]) Key() K {
+	return n.n.key
+}
+
+func (n Node[K, V]) Value() V {
+	return n.n.val
+}
@@ -0,0 +1,72 @@
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// 增强数据是子树里所有值的和
+type sumItem struct {
+	val int
+	sum int
+}
+
+func sumUpdate(v, left, right *sumItem) {
+	v.sum = v.val
+	if left != nil {
+		v.sum += left.sum
+	}
+	if right != nil {
+		v.sum += right.sum
+	}
+}
+
+// 检查每个节点的sum, 返回子树的和
+func checkSum(t *testing.T, n Node[int, sumItem]) int {
+	if !n.Valid() {
+		return 0
+	}
+
+	sum := n.Value().val + checkSum(t, n.Left()) + checkSum(t, n.Right())
+	if n.Value().sum != sum {
+		t.Fatalf("key %d Expected sum %d, got %d", n.Key(), sum, n.Value().sum)
+	}
+	return sum
+}
+
+// 随机插入, 删除, 替换, 每个节点的增强数据都要正确
+func Test_AugmentTree_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a := NewAugment[int, sumItem](sumUpdate)
+	total := 0
+
+	for i := 0; i < 3000; i++ {
+		k := r.Intn(200)
+		switch r.Intn(4) {
+		case 0:
+			c := a.Cursor()
+			if c.Seek(k) {
+				total -= c.Value().val
+				c.Delete()
+			}
+		case 1:
+			prev, replaced := a.Swap(k, sumItem{val: i})
+			if replaced {
+				total -= prev.val
+			}
+			total += i
+		default:
+			a.Insert(k, sumItem{val: i})
+			total += i
+		}
+
+		if i%100 == 0 {
+			if err := a.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := checkSum(t, a.Root()); got != total {
+			t.Fatalf("Expected total %d, got %d", total, got)
+		}
+	}
+}
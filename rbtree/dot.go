@@ -0,0 +1,44 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"io"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// 输出graphviz dot格式, 节点按红黑树的颜色填充
+// opts为nil时使用默认的选项
+func (r *RBTree[K, V]) WriteDOT(w io.Writer, opts *dot.Options[K, V]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph rbtree {")
+	dw.Printf("\tnode [style=filled, fontcolor=white];")
+	if r.root.node != nil {
+		r.root.node.writeDOT(dw, 0)
+	}
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 返回节点名
+func (n *node[K, V]) writeDOT(w *idot.Writer[K, V], depth int) string {
+	if w.Truncated(depth) {
+		return w.Ellipsis()
+	}
+
+	id := w.NextID()
+	color := "black"
+	if n.color == RED {
+		color = "red"
+	}
+	w.Printf("\t%s [label=%s, fillcolor=%s];", id, idot.Quote(w.Label(n.key, n.val)), color)
+
+	if n.left != nil {
+		w.Printf("\t%s -> %s [label=L];", id, n.left.writeDOT(w, depth+1))
+	}
+	if n.right != nil {
+		w.Printf("\t%s -> %s [label=R];", id, n.right.writeDOT(w, depth+1))
+	}
+	return id
+}
@@ -0,0 +1,50 @@
+package rbtree
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+func Test_RBTree_WriteDOT(t *testing.T) {
+	r := New[int, string]()
+	for i := 0; i < 10; i++ {
+		r.Set(i, "v")
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph rbtree {") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	// 10个节点, 9条边
+	if n := strings.Count(out, "fillcolor="); n != 10 {
+		t.Fatalf("got %d nodes", n)
+	}
+	if n := strings.Count(out, "->"); n != 9 {
+		t.Fatalf("got %d edges", n)
+	}
+	if !strings.Contains(out, "fillcolor=red") {
+		t.Fatal("should have red nodes")
+	}
+
+	// 只输出两层, 下面的用...代替
+	buf.Reset()
+	opts := &dot.Options[int, string]{MaxDepth: 2, FormatValue: func(v string) string { return v }}
+	if err := r.WriteDOT(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	out = buf.String()
+	if n := strings.Count(out, "fillcolor="); n != 3 {
+		t.Fatalf("got %d nodes:\n%s", n, out)
+	}
+	if !strings.Contains(out, `"..."`) || !strings.Contains(out, `: v"`) {
+		t.Fatalf("bad output:\n%s", out)
+	}
+}
@@ -0,0 +1,125 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*MultiTree[int, int])(nil)
+
+// 允许重复key的红黑树, 和c++的std::multimap类似
+// 相同的key按插入的顺序排列, Range, TopMin, TopMax, Cursor, RangeBetween都可以直接使用
+// 每个节点记录子树的节点个数, Count是O(log n)
+type MultiTree[K constraints.Ordered, V any] struct {
+	RBTree[K, V]
+}
+
+// 初始化函数
+func NewMulti[K constraints.Ordered, V any]() *MultiTree[K, V] {
+	m := &MultiTree[K, V]{}
+	m.root.withSize = true
+	return m
+}
+
+// 插入, key存在也会新加一个节点, 放在相同key的最后面
+func (m *MultiTree[K, V]) Insert(k K, v V) {
+	link := &m.root.node
+	var parent *node[K, V]
+	for *link != nil {
+		parent = *link
+		if parent.key <= k {
+			link = &parent.right
+		} else {
+			link = &parent.left
+		}
+	}
+
+	n := &node[K, V]{pair: pair[K, V]{key: k, val: v}}
+	n.link(parent, link)
+	m.root.insert(n)
+	m.length++
+}
+
+// 和Insert是同义词
+func (m *MultiTree[K, V]) Set(k K, v V) {
+	m.Insert(k, v)
+}
+
+// 替换最早插入的那个值, key不存在就新加
+func (m *MultiTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	n := m.root.lowerBound(k)
+	if n == nil || n.key != k {
+		m.Insert(k, v)
+		return
+	}
+
+	prev, n.val = n.val, v
+	m.root.valueChanged(n)
+	return prev, true
+}
+
+// key存在就用cb的返回值替换最早插入的那个值, 否则新加
+func (m *MultiTree[K, V]) InsertOrUpdate(k K, v V, cb InsertOrUpdateCb[V]) {
+	if prev, ok := m.TryGet(k); ok {
+		v = cb(prev, v)
+	}
+	m.Swap(k, v)
+}
+
+// 获取最早插入的值
+func (m *MultiTree[K, V]) Get(k K) (v V) {
+	v, _ = m.TryGet(k)
+	return
+}
+
+// 获取最早插入的值, 找到ok为true
+func (m *MultiTree[K, V]) TryGet(k K) (v V, ok bool) {
+	n := m.root.lowerBound(k)
+	if n == nil || n.key != k {
+		return
+	}
+	return n.val, true
+}
+
+// 按插入的顺序遍历k对应的所有值
+func (m *MultiTree[K, V]) GetAll(k K, callback func(v V) bool) {
+	for n := m.root.lowerBound(k); n != nil && n.key == k; n = n.next() {
+		if !callback(n.val) {
+			return
+		}
+	}
+}
+
+// 返回k对应值的个数, O(log n)
+func (m *MultiTree[K, V]) Count(k K) int {
+	return m.root.rank(k, true) - m.root.rank(k, false)
+}
+
+// 删除k最早插入的那个值
+func (m *MultiTree[K, V]) DeleteOne(k K) (deleted bool) {
+	n := m.root.lowerBound(k)
+	if n == nil || n.key != k {
+		return false
+	}
+
+	m.root.erase(n)
+	m.length--
+	return true
+}
+
+// 删除k对应的所有值, 返回删除的个数
+func (m *MultiTree[K, V]) DeleteAll(k K) (deleted int) {
+	for n := m.root.lowerBound(k); n != nil && n.key == k; deleted++ {
+		next := n.next()
+		m.root.erase(n)
+		m.length--
+		n = next
+	}
+	return
+}
+
+// 和DeleteAll是同义词
+func (m *MultiTree[K, V]) Delete(k K) {
+	m.DeleteAll(k)
+}
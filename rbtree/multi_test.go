@@ -0,0 +1,131 @@
+package rbtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// 和map[int][]int对比, 相同的key按插入顺序排列
+func checkMulti(t *testing.T, m *MultiTree[int, int], model map[int][]int) {
+	t.Helper()
+	checkRB(t, m.root.node)
+	checkSize(t, m.root.node)
+
+	keys := make([]int, 0, len(model))
+	total := 0
+	for k, vs := range model {
+		keys = append(keys, k)
+		total += len(vs)
+	}
+	sort.Ints(keys)
+
+	if m.Len() != total {
+		t.Fatalf("len:%d, want:%d", m.Len(), total)
+	}
+
+	var got, want [][2]int
+	for _, k := range keys {
+		for _, v := range model[k] {
+			want = append(want, [2]int{k, v})
+		}
+		if m.Count(k) != len(model[k]) {
+			t.Fatalf("count(%d):%d, want:%d", k, m.Count(k), len(model[k]))
+		}
+
+		i := 0
+		m.GetAll(k, func(v int) bool {
+			if v != model[k][i] {
+				t.Fatalf("GetAll(%d)[%d]:%d, want:%d", k, i, v, model[k][i])
+			}
+			i++
+			return true
+		})
+	}
+
+	m.Range(func(k, v int) bool {
+		got = append(got, [2]int{k, v})
+		return true
+	})
+	if len(got) != len(want) {
+		t.Fatalf("range len:%d, want:%d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("range[%d]:%v, want:%v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_MultiTree_Random(t *testing.T) {
+	m := NewMulti[int, int]()
+	model := map[int][]int{}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(100)
+		switch r.Intn(5) {
+		case 0:
+			if m.DeleteOne(k) != (len(model[k]) > 0) {
+				t.Fatalf("DeleteOne(%d)", k)
+			}
+			if len(model[k]) > 0 {
+				model[k] = model[k][1:]
+			}
+		case 1:
+			if n := m.DeleteAll(k); n != len(model[k]) {
+				t.Fatalf("DeleteAll(%d):%d, want:%d", k, n, len(model[k]))
+			}
+			model[k] = nil
+		default:
+			m.Insert(k, i)
+			model[k] = append(model[k], i)
+		}
+
+		if len(model[k]) == 0 {
+			delete(model, k)
+		}
+
+		if i%500 == 0 {
+			checkMulti(t, m, model)
+		}
+	}
+	checkMulti(t, m, model)
+}
+
+func Test_MultiTree_Get(t *testing.T) {
+	m := NewMulti[int, string]()
+	m.Insert(1, "a")
+	m.Insert(2, "b")
+	m.Insert(1, "c")
+	m.Insert(0, "d")
+
+	if v, ok := m.TryGet(1); !ok || v != "a" {
+		t.Fatalf("got %s", v)
+	}
+	if _, ok := m.TryGet(3); ok {
+		t.Fatal("3 should not exist")
+	}
+
+	// Swap替换最早插入的值
+	if prev, replaced := m.Swap(1, "e"); !replaced || prev != "a" {
+		t.Fatalf("prev:%s, replaced:%t", prev, replaced)
+	}
+	if m.Count(1) != 2 || m.Get(1) != "e" {
+		t.Fatalf("count:%d, get:%s", m.Count(1), m.Get(1))
+	}
+
+	var top []string
+	m.TopMax(2, func(k int, v string) bool {
+		top = append(top, v)
+		return true
+	})
+	if len(top) != 2 || top[0] != "b" || top[1] != "c" {
+		t.Fatalf("TopMax:%v", top)
+	}
+
+	m.Delete(1)
+	if m.Count(1) != 0 || m.Len() != 2 {
+		t.Fatalf("count:%d, len:%d", m.Count(1), m.Len())
+	}
+}
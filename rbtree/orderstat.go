@@ -0,0 +1,95 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*OrderStatTree[int, int])(nil)
+
+// 顺序统计树
+// 每个节点记录子树的节点个数, 在红黑树的基础上支持按排名查找
+// 除了下面的方法, RBTree的方法都可以直接使用
+type OrderStatTree[K constraints.Ordered, V any] struct {
+	RBTree[K, V]
+}
+
+// 初始化函数
+func NewOrderStat[K constraints.Ordered, V any]() *OrderStatTree[K, V] {
+	o := &OrderStatTree[K, V]{}
+	o.root.withSize = true
+	return o
+}
+
+// 返回排名为i的节点, 从0开始
+func (r *root[K, V]) selectNode(i int) *node[K, V] {
+	n := r.node
+	for n != nil {
+		ls := n.leftSize()
+		switch {
+		case i < ls:
+			n = n.left
+		case i > ls:
+			i -= ls + 1
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// 返回比k小的元素个数, inclusive为true时返回小于等于k的元素个数
+func (r *root[K, V]) rank(k K, inclusive bool) (rank int) {
+	n := r.node
+	for n != nil {
+		if n.key < k || inclusive && n.key == k {
+			rank += n.leftSize() + 1
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return
+}
+
+// 返回第i小的元素, i从0开始
+func (o *OrderStatTree[K, V]) Select(i int) (k K, v V, ok bool) {
+	n := o.root.selectNode(i)
+	if n == nil {
+		return
+	}
+	return n.key, n.val, true
+}
+
+// 返回比k小的元素个数, 如果k存在, 也就是k的排名(从0开始)
+func (o *OrderStatTree[K, V]) Rank(k K) int {
+	return o.root.rank(k, false)
+}
+
+// 返回[lo, hi)区间的元素个数, 可以通过bound修改区间的开闭
+func (o *OrderStatTree[K, V]) CountRange(lo, hi K, bound ...api.Bound) int {
+	bd := rangeop.GetBound(bound)
+	n := o.root.rank(hi, bd.IncludeHi()) - o.root.rank(lo, !bd.IncludeLo())
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// 按排名遍历[i, j)区间的元素
+func (o *OrderStatTree[K, V]) RangeByIndex(i, j int, callback func(k K, v V) bool) {
+	if i < 0 {
+		i = 0
+	}
+
+	n := o.root.selectNode(i)
+	for ; n != nil && i < j; i++ {
+		if !callback(n.key, n.val) {
+			return
+		}
+		n = n.next()
+	}
+}
@@ -0,0 +1,113 @@
+package rbtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+)
+
+// 检查每个节点的size是否正确
+func checkSize(t *testing.T, n *node[int, int]) int {
+	if n == nil {
+		return 0
+	}
+
+	size := checkSize(t, n.left) + checkSize(t, n.right) + 1
+	if n.size != size {
+		t.Fatalf("key:%d, Expected size %d, got %d", n.key, size, n.size)
+	}
+	return size
+}
+
+// 随机插入删除, 和排序后的slice对比
+func Test_OrderStat_Random(t *testing.T) {
+	o := NewOrderStat[int, int]()
+	m := map[int]bool{}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			o.Delete(k)
+			delete(m, k)
+		} else {
+			o.Set(k, k*2)
+			m[k] = true
+		}
+
+		if i%500 == 0 {
+			checkSize(t, o.root.node)
+		}
+	}
+	checkSize(t, o.root.node)
+
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	if o.Len() != len(keys) {
+		t.Fatalf("Expected %d, got %d", len(keys), o.Len())
+	}
+
+	for i, need := range keys {
+		k, v, ok := o.Select(i)
+		if !ok || k != need || v != need*2 {
+			t.Errorf("Select(%d) Expected %d, got %d %d %t", i, need, k, v, ok)
+		}
+	}
+	if _, _, ok := o.Select(len(keys)); ok {
+		t.Errorf("Expected false, got true")
+	}
+
+	for k := -1; k <= 1001; k++ {
+		need := sort.SearchInts(keys, k)
+		if got := o.Rank(k); got != need {
+			t.Errorf("Rank(%d) Expected %d, got %d", k, need, got)
+		}
+	}
+}
+
+// CountRange和RangeByIndex
+func Test_OrderStat_CountRange(t *testing.T) {
+	o := NewOrderStat[int, int]()
+	for i := 0; i < 100; i++ {
+		o.Set(i*2, i)
+	}
+
+	for _, bd := range []api.Bound{api.ClosedOpen, api.Closed, api.OpenClosed, api.Open} {
+		for lo := -2; lo < 202; lo += 3 {
+			for hi := lo - 3; hi < 202; hi += 5 {
+				need := 0
+				o.RangeBetween(lo, hi, func(k, v int) bool {
+					need++
+					return true
+				}, bd)
+				if got := o.CountRange(lo, hi, bd); got != need {
+					t.Errorf("bound:%d [%d, %d] Expected %d, got %d", bd, lo, hi, need, got)
+				}
+			}
+		}
+	}
+
+	var got []int
+	o.RangeByIndex(10, 15, func(k, v int) bool {
+		got = append(got, v)
+		return true
+	})
+	if !slicesEqual(got, []int{10, 11, 12, 13, 14}) {
+		t.Errorf("Expected %v, got %v", []int{10, 11, 12, 13, 14}, got)
+	}
+
+	got = got[:0]
+	o.RangeByIndex(98, 200, func(k, v int) bool {
+		got = append(got, v)
+		return true
+	})
+	if !slicesEqual(got, []int{98, 99}) {
+		t.Errorf("Expected %v, got %v", []int{98, 99}, got)
+	}
+}
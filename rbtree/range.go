@@ -0,0 +1,25 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/internal/rangeop"
+)
+
+// 升序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+// 直接从lo开始, 不需要从头遍历
+func (r *RBTree[K, V]) RangeBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.Between[K, V](r.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 降序遍历[lo, hi)区间, 可以通过bound修改区间的开闭
+func (r *RBTree[K, V]) RangePrevBetween(lo, hi K, callback func(k K, v V) bool, bound ...api.Bound) {
+	rangeop.PrevBetween[K, V](r.Cursor(), lo, hi, callback, rangeop.GetBound(bound))
+}
+
+// 删除[lo, hi)区间的所有元素, 返回删除的个数, 可以通过bound修改区间的开闭
+// 不是批量删除: 每个元素单独删除并且重新平衡, 复杂度是O(k*log(n)), k是删除的个数
+// 经常要删除大区间(比如过期数据)可以用avltree, 它的DeleteRange是O(log n)
+func (r *RBTree[K, V]) DeleteRange(lo, hi K, bound ...api.Bound) (n int) {
+	return rangeop.Delete[K, V](r.Cursor(), lo, hi, rangeop.GetBound(bound))
+}
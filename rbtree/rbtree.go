@@ -44,6 +44,26 @@ type node[K constraints.Ordered, V any] struct {
 	right *node[K, V]
 	pair[K, V]
 	parentColor[K, V]
+	// 子树的节点个数, 只有OrderStatTree才会维护
+	size int
+}
+
+func (n *node[K, V]) leftSize() int {
+	if n.left != nil {
+		return n.left.size
+	}
+	return 0
+}
+
+func (n *node[K, V]) rightSize() int {
+	if n.right != nil {
+		return n.right.size
+	}
+	return 0
+}
+
+func (n *node[K, V]) sizeUpdate() {
+	n.size = n.leftSize() + n.rightSize() + 1
 }
 
 func (n *node[K, V]) setParent(parent *node[K, V]) {
@@ -58,6 +78,52 @@ func (n *node[K, V]) link(parent *node[K, V], link **node[K, V]) {
 
 type root[K constraints.Ordered, V any] struct {
 	node *node[K, V]
+	// 为true时, 旋转, 插入, 删除都会维护node.size
+	withSize bool
+	// 不为nil时, 和size同一个时机重新计算节点上的增强数据
+	augment AugmentFunc[V]
+}
+
+func (r *root[K, V]) augmented() bool {
+	return r.withSize || r.augment != nil
+}
+
+// 根据左右孩子重新计算n上的size和增强数据
+func (r *root[K, V]) update(n *node[K, V]) {
+	if r.withSize {
+		n.sizeUpdate()
+	}
+
+	if r.augment != nil {
+		var left, right *V
+		if n.left != nil {
+			left = &n.left.val
+		}
+		if n.right != nil {
+			right = &n.right.val
+		}
+		r.augment(&n.val, left, right)
+	}
+}
+
+// 从n开始往上, 重新计算路径上每个节点
+func (r *root[K, V]) updatePath(n *node[K, V]) {
+	if !r.augmented() {
+		return
+	}
+
+	for ; n != nil; n = n.parent {
+		r.update(n)
+	}
+}
+
+// 节点的值被替换之后, 增强数据也要跟着更新
+func (r *root[K, V]) valueChanged(n *node[K, V]) {
+	if r.augment != nil {
+		for ; n != nil; n = n.parent {
+			r.update(n)
+		}
+	}
 }
 
 func (r *root[K, V]) rotateLeft(n *node[K, V]) {
@@ -83,6 +149,12 @@ func (r *root[K, V]) rotateLeft(n *node[K, V]) {
 		r.node = right
 	}
 	n.parent = right
+
+	// right接替了n的位置, 先算下面的n, 再算上面的right
+	if r.augmented() {
+		r.update(n)
+		r.update(right)
+	}
 }
 
 func (r *root[K, V]) rotateRight(n *node[K, V]) {
@@ -104,6 +176,11 @@ func (r *root[K, V]) rotateRight(n *node[K, V]) {
 		r.node = left
 	}
 	n.parent = left
+
+	if r.augmented() {
+		r.update(n)
+		r.update(left)
+	}
 }
 
 func (r *root[K, V]) changeChild(old, new, parent *node[K, V]) {
@@ -123,6 +200,9 @@ func (r *root[K, V]) insert(n *node[K, V]) {
 
 	var parent, gparent *node[K, V]
 
+	// 新节点路径上的所有子树都多了一个节点
+	r.updatePath(n)
+
 	for parent = n.parent; parent != nil && parent.color == RED; parent = n.parent {
 
 		gparent = parent.parent
@@ -232,6 +312,7 @@ func (r *RBTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
 		if parent.key == k {
 			prev = parent.val
 			parent.val = v
+			r.root.valueChanged(parent)
 			return prev, true
 		}
 
@@ -284,8 +365,9 @@ func (r *root[K, V]) erase(n *node[K, V]) {
 	} else {
 		old := n
 		n = n.right
-		left := n.left
-		for ; left != nil; left = n.left {
+		// 找到右子树里最小的节点
+		for left := n.left; left != nil; left = n.left {
+			n = left
 		}
 		child = n.right
 		parent = n.parent
@@ -346,6 +428,9 @@ func (r *root[K, V]) erase(n *node[K, V]) {
 	}
 
 color:
+	// 从实际摘掉节点的位置开始, 往上每个子树都少了一个节点
+	r.updatePath(parent)
+
 	if color == BLACK {
 		r.eraseColor(child, parent)
 	}
@@ -451,7 +536,7 @@ func (r *RBTree[K, V]) Delete(k K) {
 
 found:
 	r.root.erase(n)
-	return
+	r.length--
 }
 
 func (r *RBTree[K, V]) Len() int {
@@ -262,3 +262,35 @@ func slicesEqual[T comparable](a, b []T) bool {
 	}
 	return true
 }
+
+// 删除有两个孩子的节点, 要一直往左找到右子树里最小的节点
+func Test_RBTree_DeleteTwoChildren(t *testing.T) {
+	for del := 0; del < 100; del++ {
+		r := New[int, int]()
+		for i := 0; i < 100; i++ {
+			r.Set(i, i)
+		}
+
+		r.Delete(del)
+		for k := 0; k < 100; k++ {
+			if _, ok := r.TryGet(k); ok != (k != del) {
+				t.Fatalf("after delete %d, key %d found %v", del, k, ok)
+			}
+		}
+	}
+}
+
+// 删除存在的key长度减1, 删除不存在的key长度不变
+func Test_Delete_Len(t *testing.T) {
+	b := New[int, int]()
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+	for i := 0; i < 100; i++ {
+		b.Delete(i)
+		b.Delete(i)
+		if b.Len() != 99-i {
+			t.Fatalf("after delete %d, len %d, want %d", i, b.Len(), 99-i)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"math/bits"
+
+	"github.com/antlabs/gstl/internal/bulk"
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	ErrNotSorted    = bulk.ErrNotSorted
+	ErrDuplicateKey = bulk.ErrDuplicateKey
+	ErrLenMismatch  = bulk.ErrLenMismatch
+)
+
+// 从升序的keys, vals构造红黑树, O(n)
+// keys不是升序或者有重复的key会返回错误
+func FromSorted[K constraints.Ordered, V any](keys []K, vals []V) (*RBTree[K, V], error) {
+	f, err := bulk.Slices(keys, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSortedFunc(len(keys), f)
+}
+
+// 从升序的n个元素构造红黑树, O(n), f按0到n-1的顺序调用
+// key不是升序或者有重复的key会返回错误, 发现错误之后不会再调用f
+func FromSortedFunc[K constraints.Ordered, V any](n int, f func(i int) (K, V)) (*RBTree[K, V], error) {
+	r := New[K, V]()
+	if n <= 0 {
+		return r, nil
+	}
+
+	it := bulk.NewIter(f)
+	// 完全平衡的树, 最底下一层染红, 其他都是黑色, 每条路径的黑高相同
+	redDepth := bits.Len(uint(n)) - 1
+	r.root.node = buildSorted(it, nil, 0, n, 0, redDepth)
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	r.length = n
+	return r, nil
+}
+
+// 中序构造[lo, hi)区间的子树, 返回子树的根
+func buildSorted[K constraints.Ordered, V any](it *bulk.Iter[K, V], parent *node[K, V], lo, hi, depth, redDepth int) *node[K, V] {
+	if lo >= hi || it.Err() != nil {
+		return nil
+	}
+
+	mid := lo + (hi-lo)/2
+	n := &node[K, V]{}
+	n.parent = parent
+	n.color = BLACK
+	if depth == redDepth && depth > 0 {
+		n.color = RED
+	}
+
+	n.left = buildSorted(it, n, lo, mid, depth+1, redDepth)
+	n.key, n.val = it.Next()
+	n.right = buildSorted(it, n, mid+1, hi, depth+1, redDepth)
+	return n
+}
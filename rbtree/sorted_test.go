@@ -0,0 +1,106 @@
+package rbtree
+
+import (
+	"testing"
+)
+
+// 检查红黑树的性质, 返回黑高
+func checkRB(t *testing.T, n *node[int, int]) int {
+	if n == nil {
+		return 1
+	}
+
+	if n.color == RED {
+		if n.left != nil && n.left.color == RED || n.right != nil && n.right.color == RED {
+			t.Fatalf("red node %d has red child", n.key)
+		}
+	}
+	for _, c := range []*node[int, int]{n.left, n.right} {
+		if c != nil && c.parent != n {
+			t.Fatalf("node %d: wrong parent", c.key)
+		}
+	}
+
+	lh, rh := checkRB(t, n.left), checkRB(t, n.right)
+	if lh != rh {
+		t.Fatalf("node %d: black height %d != %d", n.key, lh, rh)
+	}
+	if n.color == BLACK {
+		lh++
+	}
+	return lh
+}
+
+func Test_RBTree_FromSorted(t *testing.T) {
+	for n := 0; n < 300; n++ {
+		keys := make([]int, n)
+		vals := make([]int, n)
+		for i := range keys {
+			keys[i] = i * 2
+			vals[i] = i
+		}
+
+		r, err := FromSorted(keys, vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Len() != n {
+			t.Fatalf("n:%d, len:%d", n, r.Len())
+		}
+		if r.root.node != nil && r.root.node.color != BLACK {
+			t.Fatalf("n:%d, root is not black", n)
+		}
+		checkRB(t, r.root.node)
+
+		i := 0
+		r.Range(func(k, v int) bool {
+			if k != keys[i] || v != vals[i] {
+				t.Fatalf("n:%d, index %d: got (%d, %d)", n, i, k, v)
+			}
+			i++
+			return true
+		})
+
+		if n == 0 {
+			continue
+		}
+
+		// 构造好的树可以继续修改
+		r.Set(1, 1)
+		r.Delete(0)
+		checkRB(t, r.root.node)
+		if _, ok := r.TryGet(1); !ok || r.Len() != n {
+			t.Fatalf("n:%d, set/delete after FromSorted failed", n)
+		}
+	}
+}
+
+func Test_RBTree_FromSorted_Error(t *testing.T) {
+	if _, err := FromSorted([]int{1, 3, 2}, []int{1, 2, 3}); err != ErrNotSorted {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2, 2}, []int{1, 2, 3}); err != ErrDuplicateKey {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2}, []int{1}); err != ErrLenMismatch {
+		t.Fatalf("got %v", err)
+	}
+
+	r, err := FromSortedFunc(100, func(i int) (int, string) { return i, "" })
+	if err != nil || r.Len() != 100 {
+		t.Fatalf("len:%d, err:%v", r.Len(), err)
+	}
+
+	// 发现乱序之后不再调用f
+	calls := 0
+	_, err = FromSortedFunc(100, func(i int) (int, int) {
+		calls++
+		if i == 10 {
+			return 0, i
+		}
+		return i, i
+	})
+	if err != ErrNotSorted || calls != 11 {
+		t.Fatalf("err:%v, calls:%d", err, calls)
+	}
+}
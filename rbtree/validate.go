@@ -0,0 +1,90 @@
+package rbtree
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+type validator[K constraints.Ordered, V any] struct {
+	withSize bool
+	multi    bool // 允许重复的key
+	count    int
+	prev     K
+}
+
+func isRed[K constraints.Ordered, V any](n *node[K, V]) bool {
+	return n != nil && n.color == RED
+}
+
+// 中序检查子树, 返回黑高
+func (v *validator[K, V]) walk(n, parent *node[K, V]) (int, error) {
+	if n == nil {
+		return 1, nil
+	}
+
+	if n.parent != parent {
+		return 0, fmt.Errorf("rbtree: key %v has wrong parent", n.key)
+	}
+	if n.color != RED && n.color != BLACK {
+		return 0, fmt.Errorf("rbtree: key %v has invalid color %d", n.key, n.color)
+	}
+	if n.color == RED && (isRed(n.left) || isRed(n.right)) {
+		return 0, fmt.Errorf("rbtree: red key %v has red child", n.key)
+	}
+
+	lh, err := v.walk(n.left, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if v.count > 0 && (n.key < v.prev || n.key == v.prev && !v.multi) {
+		return 0, fmt.Errorf("rbtree: key %v after %v is out of order", n.key, v.prev)
+	}
+	v.prev = n.key
+	v.count++
+
+	rh, err := v.walk(n.right, n)
+	if err != nil {
+		return 0, err
+	}
+
+	if lh != rh {
+		return 0, fmt.Errorf("rbtree: key %v has black height %d on the left, %d on the right", n.key, lh, rh)
+	}
+	if v.withSize && n.size != n.leftSize()+n.rightSize()+1 {
+		return 0, fmt.Errorf("rbtree: key %v has size %d, want %d", n.key, n.size, n.leftSize()+n.rightSize()+1)
+	}
+
+	if n.color == BLACK {
+		lh++
+	}
+	return lh, nil
+}
+
+func (r *RBTree[K, V]) validate(multi bool) error {
+	if isRed(r.root.node) {
+		return fmt.Errorf("rbtree: root is red")
+	}
+
+	v := &validator[K, V]{withSize: r.root.withSize, multi: multi}
+	if _, err := v.walk(r.root.node, nil); err != nil {
+		return err
+	}
+
+	if v.count != r.length {
+		return fmt.Errorf("rbtree: Len() is %d, but has %d nodes", r.length, v.count)
+	}
+	return nil
+}
+
+// 检查红黑树的性质, key的顺序, parent指针和Len(), 有问题返回错误
+func (r *RBTree[K, V]) Validate() error {
+	return r.validate(false)
+}
+
+// 和RBTree.Validate一样, 只是允许重复的key
+func (m *MultiTree[K, V]) Validate() error {
+	return m.validate(true)
+}
@@ -0,0 +1,70 @@
+package rhashmap
+
+// apache 2.0 antlabs
+import "unsafe"
+
+// key是[]byte的hash表, 比如直接用网络buffer里的数据做key
+// 查找和删除不需要把[]byte转成string, 没有内存分配, 只有第一次插入的时候才复制key
+// hash函数和string类型的key一样, 默认是带seed的xxhash
+type BytesMap[V any] struct {
+	m HashMap[string, V]
+}
+
+func NewBytesMap[V any]() *BytesMap[V] {
+	return &BytesMap[V]{m: *New[string, V]()}
+}
+
+// 选项和NewWithOpt一样, WithHasher的key类型需要是string
+func NewBytesMapWithOpt[V any](opts ...Option) *BytesMap[V] {
+	return &BytesMap[V]{m: *NewWithOpt[string, V](opts...)}
+}
+
+// 只在调用期间使用, 不能被保存下来
+func bytesToString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+func stringToBytes(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
+}
+
+func cloneKey(s string) string {
+	return string(stringToBytes(s))
+}
+
+// 获取
+func (b *BytesMap[V]) Get(key []byte) (v V) {
+	return b.m.Get(bytesToString(key))
+}
+
+// 获取
+func (b *BytesMap[V]) TryGet(key []byte) (v V, ok bool) {
+	return b.m.TryGet(bytesToString(key))
+}
+
+// 设置
+func (b *BytesMap[V]) Set(key []byte, v V) {
+	b.Swap(key, v)
+}
+
+// 设置, 调用之后key可以被修改或者复用
+func (b *BytesMap[V]) Swap(key []byte, v V) (prev V, replaced bool) {
+	return b.m.swap(bytesToString(key), v, cloneKey)
+}
+
+// 删除
+func (b *BytesMap[V]) Delete(key []byte) {
+	b.m.Delete(bytesToString(key))
+}
+
+// 遍历, 回调函数里的key不能修改
+func (b *BytesMap[V]) Range(callback func(key []byte, v V) bool) {
+	b.m.Range(func(k string, v V) bool {
+		return callback(stringToBytes(k), v)
+	})
+}
+
+// 元素个数
+func (b *BytesMap[V]) Len() int {
+	return b.m.Len()
+}
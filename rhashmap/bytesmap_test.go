@@ -0,0 +1,76 @@
+package rhashmap
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func Test_BytesMap(t *testing.T) {
+	var m BytesMap[int]
+	want := make(map[string]int)
+	r := rand.New(rand.NewSource(1))
+	buf := make([]byte, 0, 16)
+
+	for i := 0; i < 20000; i++ {
+		buf = fmt.Appendf(buf[:0], "key%d", r.Intn(2000))
+		k := string(buf)
+		switch r.Intn(3) {
+		case 0, 1:
+			prev, replaced := m.Swap(buf, i)
+			old, ok := want[k]
+			if replaced != ok || prev != old {
+				t.Fatalf("swap %s got (%d, %v), want (%d, %v)", k, prev, replaced, old, ok)
+			}
+			want[k] = i
+		case 2:
+			m.Delete(buf)
+			delete(want, k)
+		}
+		// 复用buf不能影响已经插入的key
+		for j := range buf {
+			buf[j] = 'x'
+		}
+	}
+
+	if m.Len() != len(want) {
+		t.Fatalf("len %d, want %d", m.Len(), len(want))
+	}
+	for k, v := range want {
+		if got, ok := m.TryGet([]byte(k)); !ok || got != v {
+			t.Fatalf("get %s got (%d, %v), want %d", k, got, ok, v)
+		}
+	}
+	n := 0
+	m.Range(func(k []byte, v int) bool {
+		if want[string(k)] != v {
+			t.Fatalf("range %s got %d, want %d", k, v, want[string(k)])
+		}
+		n++
+		return true
+	})
+	if n != len(want) {
+		t.Fatalf("range %d, want %d", n, len(want))
+	}
+
+	m.Set(nil, 1)
+	if v, ok := m.TryGet([]byte{}); !ok || v != 1 {
+		t.Fatalf("empty key got (%d, %v)", v, ok)
+	}
+}
+
+func Test_BytesMap_NoAlloc(t *testing.T) {
+	m := NewBytesMapWithOpt[int](WithCap(1024))
+	key := []byte("hello world")
+	notFound := []byte("not found")
+	m.Set(key, 1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		m.Get(key)
+		m.Set(key, 2)
+		m.Delete(notFound)
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocs", allocs)
+	}
+}
@@ -0,0 +1,111 @@
+package rhashmap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/hasher"
+)
+
+type userKey struct {
+	Name string
+	Age  int8
+	ID   int64
+}
+
+// 结构体里面有string, 相等的key内存不同也要能找到
+func Test_StructKey(t *testing.T) {
+	h := New[userKey, int]()
+	for i := 0; i < 1000; i++ {
+		h.Set(userKey{Name: strings.Repeat("a", i%10), Age: int8(i), ID: int64(i)}, i)
+	}
+
+	for i := 0; i < 1000; i++ {
+		k := userKey{Name: strings.Repeat("a", i%10), Age: int8(i), ID: int64(i)}
+		if v, ok := h.TryGet(k); !ok || v != i {
+			t.Fatalf("get %v got (%d, %v)", k, v, ok)
+		}
+	}
+
+	h.Set(userKey{Name: strings.Clone("aaa"), Age: 3, ID: 3}, -1)
+	if h.Len() != 1000 {
+		t.Fatalf("len %d", h.Len())
+	}
+	h.Delete(userKey{Name: strings.Clone("aaa"), Age: 3, ID: 3})
+	if h.Len() != 999 {
+		t.Fatalf("len %d", h.Len())
+	}
+}
+
+func Test_WithHasher(t *testing.T) {
+	calls := 0
+	h := NewWithOpt[string, int](WithHasher[string](hasher.Func[string](func(k string) uint64 {
+		calls++
+		return uint64(len(k))
+	})))
+	h.Set("a", 1)
+	h.Set("b", 2)
+	if h.Get("a") != 1 || h.Get("b") != 2 || calls == 0 {
+		t.Fatalf("calls %d", calls)
+	}
+
+	calls = 0
+	h = NewWithOpt[string, int](WithHashFunc(func(k string) uint64 {
+		calls++
+		return uint64(len(k))
+	}))
+	h.Set("a", 1)
+	if h.Get("a") != 1 || calls == 0 {
+		t.Fatalf("calls %d", calls)
+	}
+
+	calls = 0
+	h = NewWithOpt[string, int](WithSeed(7), WithSeededHashFunc(func(seed uint64, k string) uint64 {
+		if seed != 7 {
+			t.Fatalf("seed %d", seed)
+		}
+		calls++
+		return 0
+	}))
+	h.Set("a", 1)
+	if h.Get("a") != 1 || calls == 0 {
+		t.Fatalf("calls %d", calls)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("key type mismatch not panic")
+		}
+	}()
+	NewWithOpt[int, int](WithHasher[string](hasher.String[string]{}))
+}
+
+func rangeKeys(h *HashMap[string, int]) (keys []string) {
+	h.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return
+}
+
+// 相同的seed遍历顺序相同, 可以用来复现问题
+func Test_WithSeed(t *testing.T) {
+	a := NewWithOpt[string, int](WithSeed(1))
+	b := NewWithOpt[string, int](WithSeed(1))
+	c := NewWithOpt[string, int](WithSeed(2))
+	for i := 0; i < 100; i++ {
+		k := strings.Repeat("k", i)
+		a.Set(k, i)
+		b.Set(k, i)
+		c.Set(k, i)
+	}
+	if !slicesEqual(rangeKeys(a), rangeKeys(b)) {
+		t.Fatal("same seed, different order")
+	}
+	if slicesEqual(rangeKeys(a), rangeKeys(c)) {
+		t.Fatal("different seed, same order")
+	}
+	if New[string, int]().calHash("k") == New[string, int]().calHash("k") {
+		t.Fatal("default seed not random")
+	}
+}
@@ -1,20 +1,41 @@
 package rhashmap
 
 // apache 2.0 antlabs
+import "github.com/antlabs/gstl/hasher"
+
 type Option interface {
 	apply(*config)
 }
 
-type hashFunc func(str string) uint64
+type hashFunc func(seed uint64, str string) uint64
 
 func (h hashFunc) apply(c *config) {
 	c.hashFunc = h
 }
 
+// 自定义字符串的hash函数, 只对string类型的key生效
 func WithHashFunc(hfunc func(str string) uint64) Option {
+	return hashFunc(func(_ uint64, str string) uint64 {
+		return hfunc(str)
+	})
+}
+
+// 和WithHashFunc一样, 多传一个这个map的seed, 用来防止构造大量冲突的key攻击
+func WithSeededHashFunc(hfunc func(seed uint64, str string) uint64) Option {
 	return hashFunc(hfunc)
 }
 
+type withSeed uint64
+
+func (s withSeed) apply(c *config) {
+	c.seed = uint64(s)
+}
+
+// 指定hash的seed, 默认每个map随机生成, 测试的时候可以用来复现
+func WithSeed(seed uint64) Option {
+	return withSeed(seed)
+}
+
 type withCap int
 
 func (wc withCap) apply(c *config) {
@@ -24,3 +45,60 @@ func (wc withCap) apply(c *config) {
 func WithCap(cap int) Option {
 	return withCap(cap)
 }
+
+type withHasher struct {
+	h any
+}
+
+func (w withHasher) apply(c *config) {
+	c.hasher = w.h
+}
+
+// 自定义key的hash函数, 相等的key必须返回相同的hash值
+// K必须和map的key类型一致
+func WithHasher[K comparable](h hasher.Hasher[K]) Option {
+	return withHasher{h: h}
+}
+
+type growRatio float64
+
+func (g growRatio) apply(c *config) {
+	c.growRatio = float64(g)
+}
+
+// 元素个数/桶数达到ratio时扩容, 默认是1, ratio必须大于0, 否则panic
+func WithGrowRatio(ratio float64) Option {
+	if !(ratio > 0) {
+		panic("rhashmap: WithGrowRatio needs ratio > 0")
+	}
+	return growRatio(ratio)
+}
+
+type forceResizeRatioOpt float64
+
+func (f forceResizeRatioOpt) apply(c *config) {
+	c.forceResizeRatio = float64(f)
+}
+
+// 调用DisableResize之后, 元素个数/桶数超过ratio还是会扩容, 默认是5, ratio必须大于0, 否则panic
+func WithForceResizeRatio(ratio float64) Option {
+	if !(ratio > 0) {
+		panic("rhashmap: WithForceResizeRatio needs ratio > 0")
+	}
+	return forceResizeRatioOpt(ratio)
+}
+
+type shrinkRatio float64
+
+func (s shrinkRatio) apply(c *config) {
+	c.shrinkRatio = float64(s)
+}
+
+// 删除之后元素个数/桶数小于ratio时自动缩容, 默认是0.1, 0表示不自动缩容, 小于0会panic
+// 扩容之后元素个数/桶数大约是grow ratio的一半, ratio要比它小, 不然会反复扩容缩容, NewWithOpt会检查
+func WithShrinkRatio(ratio float64) Option {
+	if !(ratio >= 0) {
+		panic("rhashmap: WithShrinkRatio needs ratio >= 0")
+	}
+	return shrinkRatio(ratio)
+}
@@ -0,0 +1,126 @@
+package rhashmap
+
+// apache 2.0 antlabs
+import "math/rand"
+
+// FairRandomKey采样的元素个数
+const fairRandomSamples = 15
+
+// 参考redis的dictGetRandomKey, 随机返回一个元素
+// 先随机选一个非空的桶, 再在链表里面随机选一个, 链表长度不同, 所以不是完全均匀的
+func (h *HashMap[K, V]) RandomKey() (k K, v V, ok bool) {
+	if h.Len() == 0 {
+		return
+	}
+
+	if h.isRehashing() {
+		h.rehash(1)
+	}
+
+	var e *entry[K, V]
+	if h.isRehashing() {
+		s0, s1 := hashSize(h.sizeExp[0]), hashSize(h.sizeExp[1])
+		// table[0]里面rehashidx之前的桶都已经搬走了
+		start := uint64(h.rehashidx)
+		for e == nil {
+			i := start + rand.Uint64()%(s0+s1-start)
+			if i >= s0 {
+				e = h.table[1][i-s0]
+			} else {
+				e = h.table[0][i]
+			}
+		}
+	} else {
+		mask := sizeMask(h.sizeExp[0])
+		for e == nil {
+			e = h.table[0][rand.Uint64()&mask]
+		}
+	}
+
+	n := 0
+	for p := e; p != nil; p = p.next {
+		n++
+	}
+	for i := rand.Intn(n); i > 0; i-- {
+		e = e.next
+	}
+	return e.key, e.val, true
+}
+
+// 参考redis的dictGetSomeKeys, 从一个随机的位置开始, 连续取最多n个key
+// 比调用n次RandomKey快, 但是分布不均匀, 也可能有重复的key, 返回的个数也可能少于n
+func (h *HashMap[K, V]) SomeKeys(n int) []K {
+	if n > h.Len() {
+		n = h.Len()
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	for j := 0; j < n && h.isRehashing(); j++ {
+		h.rehash(1)
+	}
+
+	tables := 1
+	maxMask := sizeMask(h.sizeExp[0])
+	if h.isRehashing() {
+		tables = 2
+		if m := sizeMask(h.sizeExp[1]); m > maxMask {
+			maxMask = m
+		}
+	}
+
+	keys := make([]K, 0, n)
+	i := rand.Uint64() & maxMask
+	emptyLen := 0
+	for steps := n * 10; len(keys) < n && steps > 0; steps-- {
+		for j := 0; j < tables; j++ {
+			// table[0]里面rehashidx之前的桶都是空的
+			if tables == 2 && j == 0 && i < uint64(h.rehashidx) {
+				// 缩容的时候table[1]更小, i在table[1]里面也越界, 直接跳到rehashidx
+				if i >= hashSize(h.sizeExp[1]) {
+					i = uint64(h.rehashidx)
+				} else {
+					continue
+				}
+			}
+			if i >= hashSize(h.sizeExp[j]) {
+				continue
+			}
+
+			e := h.table[j][i]
+			if e == nil {
+				// 连续的空桶太多, 换一个随机的位置
+				emptyLen++
+				if emptyLen >= 5 && emptyLen > n {
+					i = rand.Uint64() & maxMask
+					emptyLen = 0
+				}
+				continue
+			}
+
+			emptyLen = 0
+			for ; e != nil; e = e.next {
+				keys = append(keys, e.key)
+				if len(keys) == n {
+					return keys
+				}
+			}
+		}
+		i = (i + 1) & maxMask
+	}
+	return keys
+}
+
+// 参考redis的dictGetFairRandomKey, 先用SomeKeys采样, 再从样本里面随机选一个
+// 长链表里的元素不会比短链表里的元素更难选到, 分布比RandomKey均匀
+func (h *HashMap[K, V]) FairRandomKey() (k K, v V, ok bool) {
+	keys := h.SomeKeys(fairRandomSamples)
+	if len(keys) == 0 {
+		return h.RandomKey()
+	}
+
+	k = keys[rand.Intn(len(keys))]
+	v, ok = h.TryGet(k)
+	return
+}
@@ -0,0 +1,106 @@
+package rhashmap
+
+import (
+	"testing"
+)
+
+// 随机取很多次, 所有key都应该被取到, 返回的值要和key对应
+func checkRandom(t *testing.T, h *HashMap[int, int], n int, random func() (int, int, bool)) {
+	seen := make(map[int]bool)
+	for i := 0; i < n*200 && len(seen) < n; i++ {
+		k, v, ok := random()
+		if !ok || v != k*10 {
+			t.Fatalf("random got (%d, %d, %v)", k, v, ok)
+		}
+		seen[k] = true
+	}
+	if len(seen) != n {
+		t.Fatalf("seen %d keys, want %d", len(seen), n)
+	}
+}
+
+func newRandomMap(n int, opts ...Option) *HashMap[int, int] {
+	h := NewWithOpt[int, int](opts...)
+	for i := 0; i < n; i++ {
+		h.Set(i, i*10)
+	}
+	for h.isRehashing() {
+		h.rehash(100)
+	}
+	return h
+}
+
+func Test_RandomKey(t *testing.T) {
+	h := New[int, int]()
+	if _, _, ok := h.RandomKey(); ok {
+		t.Fatal("random key from empty map")
+	}
+	if _, _, ok := h.FairRandomKey(); ok {
+		t.Fatal("fair random key from empty map")
+	}
+	if h.SomeKeys(10) != nil {
+		t.Fatal("some keys from empty map")
+	}
+
+	h = newRandomMap(100)
+	checkRandom(t, h, 100, h.RandomKey)
+	checkRandom(t, h, 100, h.FairRandomKey)
+}
+
+// rehash过程中两个table都有元素
+func Test_RandomKey_Rehashing(t *testing.T) {
+	for _, size := range []uint64{1 << 12, 64} {
+		// SomeKeys只返回一个桶前面的n个元素, 固定seed让桶里的链表长度不变
+		h := newRandomMap(100, WithSeed(1))
+		// 删掉一半之后可以缩容
+		if size == 64 {
+			for i := 50; i < 100; i++ {
+				h.Delete(i)
+			}
+		}
+		n := h.Len()
+		if err := h.Resize(size); err != nil {
+			t.Fatal(err)
+		}
+		h.rehash(10)
+		if !h.isRehashing() || h.used[0] == 0 || h.used[1] == 0 {
+			t.Fatalf("used %v", h.used)
+		}
+
+		h.pauseRehashing()
+		checkRandom(t, h, n, h.RandomKey)
+		checkRandom(t, h, n, h.FairRandomKey)
+
+		seen := make(map[int]bool)
+		for i := 0; i < n*1000 && len(seen) < n; i++ {
+			for _, k := range h.SomeKeys(5) {
+				if _, ok := h.TryGet(k); !ok {
+					t.Fatalf("key %d not found", k)
+				}
+				seen[k] = true
+			}
+		}
+		if len(seen) != n {
+			t.Fatalf("seen %d keys, want %d", len(seen), n)
+		}
+		h.resumeRehashing()
+	}
+}
+
+func Test_SomeKeys(t *testing.T) {
+	h := newRandomMap(1000)
+	keys := h.SomeKeys(20)
+	if len(keys) != 20 {
+		t.Fatalf("got %d keys", len(keys))
+	}
+	for _, k := range keys {
+		if _, ok := h.TryGet(k); !ok {
+			t.Fatalf("key %d not found", k)
+		}
+	}
+
+	h = newRandomMap(3)
+	if keys := h.SomeKeys(10); len(keys) != 3 {
+		t.Fatalf("got %v", keys)
+	}
+}
@@ -0,0 +1,40 @@
+package rhashmap
+
+// apache 2.0 antlabs
+import "time"
+
+// 参考redis的dictRehashMilliseconds, 空闲的时候调用, 在d时间内尽量多的rehash
+// rehash完成之后如果元素太少会继续缩容, 每次最多搬迁100个桶, 返回实际搬迁的非空桶数
+func (h *HashMap[K, V]) RehashFor(d time.Duration) int {
+	h.lazyinit()
+	if h.pauseRehash > 0 {
+		return 0
+	}
+
+	n := 0
+	start := time.Now()
+	for time.Since(start) <= d {
+		if !h.isRehashing() {
+			h.shrink()
+			if !h.isRehashing() {
+				break
+			}
+		}
+		moved, _ := h.rehash(100)
+		n += moved
+	}
+	return n
+}
+
+// 暂时禁止扩容和缩容, 比如fork之后避免大量的写时复制, 参考redis的dict_can_resize
+// 元素个数/桶数超过forceResizeRatio时还是会扩容
+func (h *HashMap[K, V]) DisableResize() {
+	h.lazyinit()
+	h.noResize = true
+}
+
+// 恢复扩容和缩容
+func (h *HashMap[K, V]) EnableResize() {
+	h.lazyinit()
+	h.noResize = false
+}
@@ -0,0 +1,189 @@
+package rhashmap
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_RehashFor(t *testing.T) {
+	h := New[int, int]()
+	for i := 0; i < 10000; i++ {
+		h.Set(i, i)
+	}
+	if err := h.Resize(1 << 16); err != nil {
+		t.Fatal(err)
+	}
+	if !h.isRehashing() {
+		t.Fatal("not rehashing")
+	}
+
+	h.pauseRehashing()
+	if n := h.RehashFor(time.Second); n != 0 {
+		t.Fatalf("rehash %d while paused", n)
+	}
+	h.resumeRehashing()
+
+	// 返回值是实际搬迁的非空桶数
+	need := 0
+	for _, e := range h.table[0][h.rehashidx:] {
+		if e != nil {
+			need++
+		}
+	}
+	if n := h.RehashFor(time.Second); n != need || h.isRehashing() {
+		t.Fatalf("rehash %d, want %d, rehashing %v", n, need, h.isRehashing())
+	}
+	if hashSize(h.sizeExp[0]) != 1<<16 || h.Len() != 10000 {
+		t.Fatalf("size %d, len %d", hashSize(h.sizeExp[0]), h.Len())
+	}
+	if h.RehashFor(time.Second) != 0 {
+		t.Fatal("rehash without rehashing")
+	}
+}
+
+// 正在rehash时Resize会先完成这次rehash
+func Test_Resize_Rehashing(t *testing.T) {
+	h := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		h.Set(i, i)
+	}
+	h.Resize(1 << 12)
+	if err := h.Resize(1 << 14); err != nil {
+		t.Fatal(err)
+	}
+
+	h.pauseRehashing()
+	if err := h.Resize(1 << 15); err != ErrHashing {
+		t.Fatalf("err %v, want %v", err, ErrHashing)
+	}
+	h.resumeRehashing()
+}
+
+func Test_GrowRatio(t *testing.T) {
+	h := NewWithOpt[int, int](WithGrowRatio(0.5))
+	for i := 0; i < 64; i++ {
+		h.Set(i, i)
+	}
+	h.RehashFor(time.Second)
+	if size := hashSize(h.sizeExp[0]); size < 128 {
+		t.Fatalf("size %d, want >= 128", size)
+	}
+
+	h = NewWithOpt[int, int](WithGrowRatio(4))
+	for i := 0; i < 64; i++ {
+		h.Set(i, i)
+	}
+	h.RehashFor(time.Second)
+	if size := hashSize(h.sizeExp[0]); size > 32 {
+		t.Fatalf("size %d, want <= 32", size)
+	}
+}
+
+func Test_DisableResize(t *testing.T) {
+	h := NewWithOpt[int, int](WithForceResizeRatio(3))
+	h.Resize(16)
+	h.DisableResize()
+	for i := 0; i < 48; i++ {
+		h.Set(i, i)
+	}
+	if h.isRehashing() || hashSize(h.sizeExp[0]) != 16 {
+		t.Fatalf("resized while disabled, size %d", hashSize(h.sizeExp[0]))
+	}
+
+	// 超过forceResizeRatio还是会扩容
+	for i := 48; i < 60; i++ {
+		h.Set(i, i)
+	}
+	if !h.isRehashing() {
+		t.Fatal("not resized after force ratio")
+	}
+	h.RehashFor(time.Second)
+
+	// 禁止resize时缩容的阈值也会变小
+	for i := 0; i < 55; i++ {
+		h.Delete(i)
+	}
+	size := hashSize(h.sizeExp[0])
+	if h.isRehashing() {
+		t.Fatal("shrink while disabled")
+	}
+	h.EnableResize()
+	h.Delete(55)
+	if !h.isRehashing() && hashSize(h.sizeExp[0]) == size {
+		t.Fatal("not shrink after enable")
+	}
+}
+
+func Test_AutoShrink(t *testing.T) {
+	h := New[int, int]()
+	for i := 0; i < 10000; i++ {
+		h.Set(i, i)
+	}
+	h.RehashFor(time.Second)
+	for i := 0; i < 9990; i++ {
+		h.Delete(i)
+	}
+	h.RehashFor(time.Second)
+	if size := hashSize(h.sizeExp[0]); size > 128 {
+		t.Fatalf("size %d after delete", size)
+	}
+	for i := 9990; i < 10000; i++ {
+		if h.Get(i) != i {
+			t.Fatalf("get %d got %d", i, h.Get(i))
+		}
+	}
+
+	h = NewWithOpt[int, int](WithShrinkRatio(0))
+	for i := 0; i < 10000; i++ {
+		h.Set(i, i)
+	}
+	h.RehashFor(time.Second)
+	size := hashSize(h.sizeExp[0])
+	for i := 0; i < 9990; i++ {
+		h.Delete(i)
+	}
+	if h.isRehashing() || hashSize(h.sizeExp[0]) != size {
+		t.Fatal("shrink with ratio 0")
+	}
+}
+
+// 会导致每次插入都扩容, 或者反复扩容缩容的参数直接panic
+func Test_RatioOptions_Invalid(t *testing.T) {
+	for name, f := range map[string]func(){
+		"grow 0":           func() { WithGrowRatio(0) },
+		"grow -1":          func() { WithGrowRatio(-1) },
+		"force 0":          func() { WithForceResizeRatio(0) },
+		"shrink -0.1":      func() { WithShrinkRatio(-0.1) },
+		"shrink >= grow":   func() { NewWithOpt[int, int](WithGrowRatio(1), WithShrinkRatio(1)) },
+		"shrink >= grow/2": func() { NewWithOpt[int, int](WithGrowRatio(1), WithShrinkRatio(0.5)) },
+		"default shrink":   func() { NewWithOpt[int, int](WithGrowRatio(0.2)) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+
+	// 合法的参数在边界上反复插入删除同一个元素, 不会来回resize
+	h := NewWithOpt[int, int](WithGrowRatio(0.5), WithShrinkRatio(0.2))
+	for i := 0; i < 64; i++ {
+		h.Set(i, i)
+	}
+	h.RehashFor(time.Second)
+	changes, size := 0, hashSize(h.sizeExp[0])
+	for i := 0; i < 100; i++ {
+		h.Set(1000, 1)
+		h.Delete(1000)
+		h.RehashFor(time.Second)
+		if s := hashSize(h.sizeExp[0]); s != size {
+			changes, size = changes+1, s
+		}
+	}
+	if changes > 1 {
+		t.Fatalf("resized %d times", changes)
+	}
+}
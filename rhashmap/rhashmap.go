@@ -10,7 +10,8 @@ import (
 	"unsafe"
 
 	"github.com/antlabs/gstl/api"
-	xxhash "github.com/cespare/xxhash/v2"
+	"github.com/antlabs/gstl/cmp"
+	"github.com/antlabs/gstl/hasher"
 )
 
 var _ api.Map[int, int] = (*HashMap[int, int])(nil)
@@ -22,6 +23,9 @@ const (
 
 var forceResizeRatio = 5
 
+// 元素个数小于桶数的10%时自动缩容
+const minFillRatio = 0.1
+
 var (
 	ErrHashing  = errors.New("rehashing...")
 	ErrSize     = errors.New("wrong size")
@@ -36,8 +40,14 @@ type entry[K comparable, V any] struct {
 }
 
 type config struct {
-	hashFunc func(str string) uint64
-	cap      int
+	hashFunc         func(seed uint64, str string) uint64
+	seed             uint64 // 默认每个map随机生成, 防止构造大量冲突的key攻击
+	hasher           any    // hasher.Hasher[K]
+	cap              int
+	growRatio        float64 // 元素个数/桶数达到这个值时扩容
+	forceResizeRatio float64 // 禁止resize时, 元素个数/桶数超过这个值还是会扩容
+	shrinkRatio      float64 // 元素个数/桶数小于这个值时缩容, 0表示不自动缩容
+	noResize         bool    // 暂时禁止resize, 比如fork之后避免写时复制
 }
 
 // hash 表头
@@ -50,11 +60,11 @@ type HashMap[K comparable, V any] struct {
 	used    [2]uint64         // 记录每个table里面存在的元素个数
 	sizeExp [2]int8           //记录exp
 
-	rehashidx int // rehashid目前的槽位
-	keySize   int //key的长度
+	rehashidx   int // rehashid目前的槽位
+	pauseRehash int // 大于0时暂停渐进式rehash, Scan和SafeRange的时候使用
+	hash        hasher.Hasher[K]
 	config
-	isKeyStr bool //是string类型的key, 或者不是
-	init     bool
+	init bool
 }
 
 // 初始化一个hashtable
@@ -67,12 +77,15 @@ func New[K comparable, V any]() *HashMap[K, V] {
 func (h *HashMap[K, V]) Init() {
 
 	h.rehashidx = -1
-	h.hashFunc = xxhash.Sum64String
+	h.seed = hasher.RandomSeed()
+	h.hash = hasher.New[K](h.seed)
+	h.growRatio = 1
+	h.forceResizeRatio = float64(forceResizeRatio)
+	h.shrinkRatio = minFillRatio
 	h.init = true
 
 	h.reset(0)
 	h.reset(1)
-	h.keyTypeAndKeySize()
 }
 
 func (h *HashMap[K, V]) lazyinit() {
@@ -87,6 +100,11 @@ func NewWithOpt[K comparable, V any](opts ...Option) *HashMap[K, V] {
 	for _, o := range opts {
 		o.apply(&h.config)
 	}
+	// 扩容之后元素个数/桶数大约是growRatio/2, shrinkRatio不小于它时删一个元素又会缩回去
+	if h.shrinkRatio >= h.growRatio/2 {
+		panic("rhashmap: shrink ratio must be less than half of grow ratio")
+	}
+	h.setHasher()
 
 	if h.cap > 0 {
 		h.Resize(uint64(h.cap))
@@ -94,33 +112,31 @@ func NewWithOpt[K comparable, V any](opts ...Option) *HashMap[K, V] {
 	return h
 }
 
-// 保存key的类型和key的长度
-func (h *HashMap[K, V]) keyTypeAndKeySize() {
+// 使用选项里面的hash函数
+func (h *HashMap[K, V]) setHasher() {
+	if h.hasher != nil {
+		hs, ok := h.hasher.(hasher.Hasher[K])
+		if !ok {
+			panic("rhashmap: WithHasher key type mismatch")
+		}
+		h.hash = hs
+		return
+	}
+
 	var k K
-	switch (interface{})(k).(type) {
-	case string:
-		h.isKeyStr = true
-	default:
-		h.keySize = int(unsafe.Sizeof(k))
+	if h.hashFunc != nil && reflect.TypeOf(&k).Elem().Kind() == reflect.String {
+		hashFunc, seed := h.hashFunc, h.seed
+		h.hash = hasher.Func[K](func(key K) uint64 {
+			return hashFunc(seed, *(*string)(unsafe.Pointer(&key)))
+		})
+		return
 	}
+	h.hash = hasher.New[K](h.seed)
 }
 
 // 计算hash值
 func (h *HashMap[K, V]) calHash(k K) uint64 {
-	var key string
-
-	if h.isKeyStr {
-		// 直接赋值会报错, 使用unsafe绕过编译器检查
-		key = *(*string)(unsafe.Pointer(&k))
-	} else {
-		// 因为xxhash.Sum64String 接收string, 所以要把非string类型变量当成string类型来处理
-		key = *(*string)(unsafe.Pointer(&reflect.StringHeader{
-			Data: uintptr(unsafe.Pointer(&k)),
-			Len:  h.keySize,
-		}))
-	}
-
-	return xxhash.Sum64String(key)
+	return h.hash.Hash(k)
 }
 
 func (h *HashMap[K, V]) isRehashing() bool {
@@ -153,18 +169,46 @@ func (h *HashMap[K, V]) expand() error {
 		return h.Resize(HT_INITIAL_SIZE)
 	}
 
-	if h.used[0] >= hashSize(h.sizeExp[0]) || h.used[0]/hashSize(h.sizeExp[0]) > uint64(forceResizeRatio) {
-		return h.Resize(h.used[0] + 1)
+	size := hashSize(h.sizeExp[0])
+	ratio := float64(h.used[0]) / float64(size)
+	if ratio >= h.growRatio && (!h.noResize || ratio > h.forceResizeRatio) {
+		return h.Resize(cmp.Max(h.used[0]+1, size*2))
 	}
 
 	return nil
 }
 
+// 删除之后检查是否需要缩容
+func (h *HashMap[K, V]) shrink() error {
+	if h.isRehashing() || h.shrinkRatio == 0 {
+		return nil
+	}
+
+	size := hashSize(h.sizeExp[0])
+	if size <= HT_INITIAL_SIZE {
+		return nil
+	}
+
+	minFill := h.shrinkRatio
+	if h.noResize {
+		minFill /= h.forceResizeRatio
+	}
+	if float64(h.used[0]) < float64(size)*minFill {
+		return h.ShrinkToFit()
+	}
+	return nil
+}
+
 // 手动修改hashtable的大小
+// 正在rehash时会先完成这次rehash
 func (h *HashMap[K, V]) Resize(size uint64) error {
 	h.lazyinit()
-	// 如果正在扩容中, 或者需要扩容的数据小于已存在的元素, 直接返回
-	if h.isRehashing() || h.used[0] > uint64(size) {
+	if err := h.finishRehash(); err != nil {
+		return err
+	}
+
+	// 需要扩容的数据小于已存在的元素, 直接返回
+	if h.used[0] > uint64(size) {
 		return ErrHashing
 	}
 
@@ -197,11 +241,25 @@ func (h *HashMap[K, V]) Resize(size uint64) error {
 	return nil
 }
 
+// 一次性完成正在进行的rehash, Scan和SafeRange期间不能rehash, 返回ErrHashing
+func (h *HashMap[K, V]) finishRehash() error {
+	if !h.isRehashing() {
+		return nil
+	}
+	if h.pauseRehash > 0 {
+		return ErrHashing
+	}
+	for h.isRehashing() {
+		h.rehash(100)
+	}
+	return nil
+}
+
 // 收缩hash table
 func (h *HashMap[K, V]) ShrinkToFit() error {
 	h.lazyinit()
-	if h.isRehashing() {
-		return ErrHashing
+	if err := h.finishRehash(); err != nil {
+		return err
 	}
 
 	minimal := h.used[0]
@@ -239,13 +297,18 @@ func (h *HashMap[K, V]) findIndexAndEntry(key K) (i uint64, e *entry[K, V], err
 	return idx, nil, nil
 }
 
-func (h *HashMap[K, V]) rehash(n int) error {
+// 最多搬迁n个桶, 返回实际搬迁的非空桶数
+func (h *HashMap[K, V]) rehash(n int) (moved int, err error) {
 	// 控制访问空槽位的个数
 	emptyVisits := n * 10
 
 	// 没有rehashing 就退出
 	if !h.isRehashing() {
-		return ErrHashing
+		return 0, ErrHashing
+	}
+
+	if h.pauseRehash > 0 {
+		return 0, nil
 	}
 
 	// n是控制桶数
@@ -255,7 +318,7 @@ func (h *HashMap[K, V]) rehash(n int) error {
 			h.rehashidx++
 			emptyVisits--
 			if emptyVisits == 0 {
-				return nil
+				return moved, nil
 			}
 		}
 
@@ -273,6 +336,7 @@ func (h *HashMap[K, V]) rehash(n int) error {
 
 		h.table[0][h.rehashidx] = nil
 		h.rehashidx++
+		moved++
 	}
 
 	if h.used[0] == 0 {
@@ -283,7 +347,7 @@ func (h *HashMap[K, V]) rehash(n int) error {
 		// 这里重装置为-1
 		h.rehashidx = -1
 	}
-	return nil
+	return moved, nil
 }
 
 func (h *HashMap[K, V]) reset(idx int) {
@@ -380,6 +444,11 @@ func (h *HashMap[K, V]) Set(k K, v V) {
 
 // 设置
 func (h *HashMap[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	return h.swap(k, v, nil)
+}
+
+// 第一次插入的时候用clone复制key, BytesMap查找时的key引用的是调用者的内存
+func (h *HashMap[K, V]) swap(k K, v V, clone func(K) K) (prev V, replaced bool) {
 	h.lazyinit()
 	if h.isRehashing() {
 		h.rehash(1)
@@ -404,6 +473,9 @@ func (h *HashMap[K, V]) Swap(k K, v V) (prev V, replaced bool) {
 		return prev, true
 	}
 
+	if clone != nil {
+		k = clone(k)
+	}
 	e = &entry[K, V]{key: k, val: v}
 	e.next = h.table[idx][index]
 	h.table[idx][index] = e
@@ -453,7 +525,7 @@ func (h *HashMap[K, V]) Remove(key K) (err error) {
 					h.table[table][idx] = head.next
 				}
 				h.used[table]--
-				return nil
+				return h.shrink()
 			}
 
 			prev = head
@@ -93,3 +93,35 @@ func BenchmarkSetStd(b *testing.B) {
 		set[i] = i
 	}
 }
+
+// []byte的key
+func BenchmarkBytesMapGet(b *testing.B) {
+	m := NewBytesMap[int]()
+	keys := make([][]byte, 1024)
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "key%d", i)
+		m.Set(keys[i], i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.Get(keys[i&1023])
+	}
+}
+
+// []byte转成string再查找
+func BenchmarkBytesMapGetString(b *testing.B) {
+	m := New[string, int]()
+	keys := make([][]byte, 1024)
+	for i := range keys {
+		keys[i] = fmt.Appendf(nil, "key%d", i)
+		m.Set(string(keys[i]), i)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		m.Get(string(keys[i&1023]))
+	}
+}
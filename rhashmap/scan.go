@@ -0,0 +1,110 @@
+package rhashmap
+
+// apache 2.0 antlabs
+import "math/bits"
+
+// 暂停渐进式rehash, 桶里的元素不会在两个table之间移动
+func (h *HashMap[K, V]) pauseRehashing() {
+	h.pauseRehash++
+}
+
+func (h *HashMap[K, V]) resumeRehashing() {
+	h.pauseRehash--
+}
+
+// 遍历一个桶, 先保存next, 回调里面删除当前元素是安全的
+func (h *HashMap[K, V]) scanBucket(table int, idx uint64, cb func(k K, v V)) (n int) {
+	for e := h.table[table][idx]; e != nil; {
+		next := e.next
+		cb(e.key, e.val)
+		e = next
+		n++
+	}
+	return n
+}
+
+// 游标的高位加1, 也就是把游标反转之后加1再反转回来
+func nextCursor(v, mask uint64) uint64 {
+	v |= ^mask
+	return bits.Reverse64(bits.Reverse64(v) + 1)
+}
+
+// 访问游标对应的桶, 返回下一个游标
+// 正在rehash时, 先访问小表的桶, 再访问大表里面所有由它展开的桶
+func (h *HashMap[K, V]) scanStep(v uint64, cb func(k K, v V)) (next uint64, n int) {
+	if !h.isRehashing() {
+		m0 := sizeMask(h.sizeExp[0])
+		n = h.scanBucket(0, v&m0, cb)
+		return nextCursor(v, m0), n
+	}
+
+	t0, t1 := 0, 1
+	if hashSize(h.sizeExp[t0]) > hashSize(h.sizeExp[t1]) {
+		t0, t1 = 1, 0
+	}
+	m0, m1 := sizeMask(h.sizeExp[t0]), sizeMask(h.sizeExp[t1])
+
+	n = h.scanBucket(t0, v&m0, cb)
+	for {
+		n += h.scanBucket(t1, v&m1, cb)
+		v = nextCursor(v, m1)
+		// 小表掩码之外的位都访问完了
+		if v&(m0^m1) == 0 {
+			break
+		}
+	}
+	return v, n
+}
+
+// 参考redis的dictScan, 从cursor开始遍历, 返回下一次的cursor, 返回0表示遍历结束
+// 第一次调用传0, 每次最少访问一个桶, 访问到count个元素或者count*10个桶就返回
+// 从开始到结束一直存在的元素至少会返回一次, 中间发生扩容或者缩容也不会漏掉, 但是可能会重复
+// 回调里面可以删除当前元素, 不能删除别的元素
+func (h *HashMap[K, V]) Scan(cursor uint64, count int, cb func(k K, v V)) (next uint64) {
+	if h.Len() == 0 {
+		return 0
+	}
+	if count <= 0 {
+		count = 10
+	}
+
+	h.pauseRehashing()
+	defer h.resumeRehashing()
+
+	visited := 0
+	for steps := count * 10; steps > 0; steps-- {
+		var n int
+		cursor, n = h.scanStep(cursor, cb)
+		visited += n
+		if cursor == 0 || visited >= count {
+			break
+		}
+	}
+	return cursor
+}
+
+// 遍历, 遍历期间暂停rehash, 回调里面可以删除当前元素
+// 回调里面插入的新元素可能不会被遍历到
+func (h *HashMap[K, V]) SafeRange(pr func(key K, val V) bool) {
+	if h.Len() == 0 {
+		return
+	}
+
+	h.pauseRehashing()
+	defer h.resumeRehashing()
+
+	for table := 0; table < 2; table++ {
+		for idx := 0; idx < len(h.table[table]); idx++ {
+			for e := h.table[table][idx]; e != nil; {
+				next := e.next
+				if !pr(e.key, e.val) {
+					return
+				}
+				e = next
+			}
+		}
+		if !h.isRehashing() {
+			break
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package rhashmap
+
+import (
+	"testing"
+)
+
+// 扫描完整个表, 返回每个key出现的次数
+func scanAll(h *HashMap[int, int], count int, each func(cursor uint64)) map[int]int {
+	seen := make(map[int]int)
+	cursor := uint64(0)
+	for {
+		cursor = h.Scan(cursor, count, func(k, v int) {
+			seen[k]++
+		})
+		if cursor == 0 {
+			return seen
+		}
+		if each != nil {
+			each(cursor)
+		}
+	}
+}
+
+func Test_Scan(t *testing.T) {
+	h := New[int, int]()
+	if h.Scan(0, 10, func(k, v int) {}) != 0 {
+		t.Fatal("scan empty map")
+	}
+
+	for i := 0; i < 1000; i++ {
+		h.Set(i, i)
+	}
+	seen := scanAll(h, 7, nil)
+	for i := 0; i < 1000; i++ {
+		if seen[i] == 0 {
+			t.Fatalf("key %d not returned", i)
+		}
+	}
+}
+
+// 扫描过程中扩容, 缩容, 或者一直处于rehash状态, 一直存在的元素都要返回
+func Test_Scan_Resize(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		resize func(t *testing.T, h *HashMap[int, int], step int)
+	}{
+		{"grow", func(t *testing.T, h *HashMap[int, int], step int) {
+			h.Set(10000+step, step)
+		}},
+		{"shrink", func(t *testing.T, h *HashMap[int, int], step int) {
+			if step == 2 {
+				for i := 500; i < 2000; i++ {
+					h.Delete(i)
+				}
+				for h.isRehashing() {
+					h.rehash(100)
+				}
+				if err := h.ShrinkToFit(); err != nil || !h.isRehashing() {
+					t.Fatalf("shrink err %v", err)
+				}
+			}
+			h.Get(0)
+		}},
+		{"resize", func(t *testing.T, h *HashMap[int, int], step int) {
+			if step == 3 {
+				h.Resize(1 << 14)
+			}
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := New[int, int]()
+			for i := 0; i < 2000; i++ {
+				h.Set(i, i)
+			}
+
+			step := 0
+			seen := scanAll(h, 5, func(cursor uint64) {
+				step++
+				tc.resize(t, h, step)
+			})
+			for i := 0; i < 500; i++ {
+				if seen[i] == 0 {
+					t.Fatalf("key %d not returned", i)
+				}
+			}
+		})
+	}
+}
+
+// 回调里面删除当前元素
+func Test_Scan_Delete(t *testing.T) {
+	h := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		h.Set(i, i)
+	}
+	h.Resize(4096)
+	if !h.isRehashing() {
+		t.Fatal("not rehashing")
+	}
+
+	cursor := uint64(0)
+	for {
+		cursor = h.Scan(cursor, 10, func(k, v int) {
+			h.Delete(k)
+		})
+		if cursor == 0 {
+			break
+		}
+	}
+	if h.Len() != 0 {
+		t.Fatalf("len %d", h.Len())
+	}
+}
+
+func Test_SafeRange(t *testing.T) {
+	h := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		h.Set(i, i)
+	}
+	h.Resize(4096)
+	h.Get(0)
+
+	n := 0
+	h.SafeRange(func(k, v int) bool {
+		if k%2 == 0 {
+			h.Delete(k)
+		} else {
+			h.Set(k, v*2)
+		}
+		n++
+		return true
+	})
+	if n != 1000 || h.Len() != 500 {
+		t.Fatalf("visited %d, len %d", n, h.Len())
+	}
+	if h.pauseRehash != 0 || !h.isRehashing() {
+		t.Fatalf("pauseRehash %d", h.pauseRehash)
+	}
+	for i := 1; i < 1000; i += 2 {
+		if h.Get(i) != i*2 {
+			t.Fatalf("get %d got %d", i, h.Get(i))
+		}
+	}
+
+	n = 0
+	h.SafeRange(func(k, v int) bool {
+		n++
+		return n < 10
+	})
+	if n != 10 {
+		t.Fatalf("visited %d", n)
+	}
+}
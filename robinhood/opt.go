@@ -0,0 +1,44 @@
+package robinhood
+
+// apache 2.0 antlabs
+import "github.com/antlabs/gstl/hasher"
+
+type Option interface {
+	apply(*config)
+}
+
+type withCap int
+
+func (wc withCap) apply(c *config) {
+	c.cap = int(wc)
+}
+
+// 预分配的容量
+func WithCap(cap int) Option {
+	return withCap(cap)
+}
+
+type withHasher struct {
+	h any
+}
+
+func (w withHasher) apply(c *config) {
+	c.hasher = w.h
+}
+
+// 自定义key的hash函数, 相等的key必须返回相同的hash值
+// K必须和map的key类型一致
+func WithHasher[K comparable](h hasher.Hasher[K]) Option {
+	return withHasher{h: h}
+}
+
+type withSeed uint64
+
+func (s withSeed) apply(c *config) {
+	c.seed = uint64(s)
+}
+
+// 指定hash的seed, 默认每个map随机生成, 测试的时候可以用来复现
+func WithSeed(seed uint64) Option {
+	return withSeed(seed)
+}
@@ -0,0 +1,299 @@
+package robinhood
+
+// apache 2.0 antlabs
+
+// 开放寻址的hash表, 使用robin hood策略减小探测长度的方差
+// 元素直接存在数组里面, 插入不需要额外分配内存
+// 删除使用backward shift, 不需要墓碑
+// 扩容和rhashmap一样是渐进式的, 每次写操作搬迁一部分老表的槽位
+// 参考资料
+// https://codecapsule.com/2013/11/17/robin-hood-hashing-backward-shift-deletion/
+import (
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/hasher"
+)
+
+var _ api.Map[int, int] = (*HashMap[int, int])(nil)
+
+const (
+	initialSize = 8
+	// 每次写操作最多搬迁的槽位数, 保证新表装满之前老表已经搬迁完
+	migrateSlots = 4
+)
+
+// 槽位
+type slot[K comparable, V any] struct {
+	dist uint32 // 0表示空槽位, 否则是离理想位置的距离+1
+	hash uint64
+	key  K
+	val  V
+}
+
+type table[K comparable, V any] struct {
+	slots []slot[K, V]
+	mask  uint64
+	used  int
+}
+
+func newTable[K comparable, V any](size int) table[K, V] {
+	return table[K, V]{slots: make([]slot[K, V], size), mask: uint64(size - 1)}
+}
+
+// 返回槽位的索引, 没有找到返回-1
+func (t *table[K, V]) find(hash uint64, key K) int {
+	if t.used == 0 {
+		return -1
+	}
+
+	i := hash & t.mask
+	for dist := uint32(1); ; dist++ {
+		s := &t.slots[i]
+		// 比当前元素离理想位置更近, 说明key不可能在后面
+		if s.dist < dist {
+			return -1
+		}
+		if s.hash == hash && s.key == key {
+			return int(i)
+		}
+		i = (i + 1) & t.mask
+	}
+}
+
+// 插入一个不存在的key, 遇到离理想位置更近的元素就交换, 劫富济贫
+func (t *table[K, V]) insert(hash uint64, key K, val V) {
+	cur := slot[K, V]{dist: 1, hash: hash, key: key, val: val}
+	i := hash & t.mask
+	for {
+		s := &t.slots[i]
+		if s.dist == 0 {
+			*s = cur
+			t.used++
+			return
+		}
+		if s.dist < cur.dist {
+			cur, *s = *s, cur
+		}
+		cur.dist++
+		i = (i + 1) & t.mask
+	}
+}
+
+// 删除i位置的元素, 后面的元素依次往前移动一格, 直到空槽位或者已经在理想位置的元素
+func (t *table[K, V]) remove(i int) {
+	j := (i + 1) & int(t.mask)
+	for t.slots[j].dist > 1 {
+		t.slots[i] = t.slots[j]
+		t.slots[i].dist--
+		i = j
+		j = (j + 1) & int(t.mask)
+	}
+	t.slots[i] = slot[K, V]{}
+	t.used--
+}
+
+type config struct {
+	cap    int
+	hasher any    // hasher.Hasher[K]
+	seed   uint64 // 默认每个map随机生成
+}
+
+// hash 表头
+type HashMap[K comparable, V any] struct {
+	cur table[K, V] // 新元素都写到cur
+	// 扩容中的老表, migrateIdx之前的槽位都已经搬迁到cur
+	old        table[K, V]
+	migrateIdx int
+	hash       hasher.Hasher[K]
+	config
+	init bool
+}
+
+// 初始化一个hashtable
+func New[K comparable, V any]() *HashMap[K, V] {
+	h := &HashMap[K, V]{}
+	h.Init()
+	return h
+}
+
+// 初始化一个hashtable并且可以设置值
+func NewWithOpt[K comparable, V any](opts ...Option) *HashMap[K, V] {
+	h := New[K, V]()
+	for _, o := range opts {
+		o.apply(&h.config)
+	}
+	h.hash = hasher.New[K](h.seed)
+	if h.hasher != nil {
+		hs, ok := h.hasher.(hasher.Hasher[K])
+		if !ok {
+			panic("robinhood: WithHasher key type mismatch")
+		}
+		h.hash = hs
+	}
+
+	if h.cap > 0 {
+		h.cur = newTable[K, V](tableSize(h.cap))
+	}
+	return h
+}
+
+func (h *HashMap[K, V]) Init() {
+	h.init = true
+	h.seed = hasher.RandomSeed()
+	h.hash = hasher.New[K](h.seed)
+}
+
+func (h *HashMap[K, V]) lazyinit() {
+	if !h.init {
+		h.Init()
+	}
+}
+
+// 计算hash值
+func (h *HashMap[K, V]) calHash(k K) uint64 {
+	return h.hash.Hash(k)
+}
+
+// 能放下n个元素的表大小, 装载因子不超过7/8
+func tableSize(n int) int {
+	size := initialSize
+	for size-size/8 < n {
+		size *= 2
+	}
+	return size
+}
+
+func (h *HashMap[K, V]) isGrowing() bool {
+	return h.old.slots != nil
+}
+
+// 搬迁老表的n个槽位
+func (h *HashMap[K, V]) migrate(n int) {
+	for ; n > 0 && h.old.used > 0; n-- {
+		s := &h.old.slots[h.migrateIdx]
+		if s.dist == 0 {
+			h.migrateIdx++
+			continue
+		}
+		// 删除之后后面的元素会移动到当前位置, 所以migrateIdx不变
+		h.cur.insert(s.hash, s.key, s.val)
+		h.old.remove(h.migrateIdx)
+	}
+
+	if h.old.used == 0 {
+		h.old = table[K, V]{}
+		h.migrateIdx = 0
+	}
+}
+
+// 写入之前检查是否需要扩容
+func (h *HashMap[K, V]) grow() {
+	if h.isGrowing() {
+		h.migrate(migrateSlots)
+	}
+
+	size := len(h.cur.slots)
+	if h.cur.used+1 <= size-size/8 {
+		return
+	}
+
+	// 上一次扩容还没有结束, 先一次性搬完
+	if h.isGrowing() {
+		h.migrate(h.old.used + len(h.old.slots))
+	}
+
+	if size == 0 {
+		h.cur = newTable[K, V](initialSize)
+		return
+	}
+	h.old = h.cur
+	h.cur = newTable[K, V](size * 2)
+}
+
+// 获取
+func (h *HashMap[K, V]) TryGet(key K) (v V, ok bool) {
+	if h.Len() == 0 {
+		return
+	}
+
+	hash := h.calHash(key)
+	if i := h.cur.find(hash, key); i >= 0 {
+		return h.cur.slots[i].val, true
+	}
+	if i := h.old.find(hash, key); i >= 0 {
+		return h.old.slots[i].val, true
+	}
+	return
+}
+
+// 获取
+func (h *HashMap[K, V]) Get(key K) (v V) {
+	v, _ = h.TryGet(key)
+	return
+}
+
+func (h *HashMap[K, V]) Set(k K, v V) {
+	h.Swap(k, v)
+}
+
+// 设置
+func (h *HashMap[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	h.lazyinit()
+	hash := h.calHash(k)
+	if i := h.cur.find(hash, k); i >= 0 {
+		prev = h.cur.slots[i].val
+		h.cur.slots[i].val = v
+		return prev, true
+	}
+	if i := h.old.find(hash, k); i >= 0 {
+		prev = h.old.slots[i].val
+		h.old.slots[i].val = v
+		return prev, true
+	}
+
+	h.grow()
+	h.cur.insert(hash, k, v)
+	return
+}
+
+// 删除
+func (h *HashMap[K, V]) Delete(key K) {
+	h.DeleteWithPrev(key)
+}
+
+// 删除, 返回被删除的值
+func (h *HashMap[K, V]) DeleteWithPrev(key K) (prev V, deleted bool) {
+	if h.Len() == 0 {
+		return
+	}
+
+	hash := h.calHash(key)
+	if i := h.cur.find(hash, key); i >= 0 {
+		prev, deleted = h.cur.slots[i].val, true
+		h.cur.remove(i)
+	} else if i := h.old.find(hash, key); i >= 0 {
+		prev, deleted = h.old.slots[i].val, true
+		h.old.remove(i)
+	}
+
+	if h.isGrowing() {
+		h.migrate(migrateSlots)
+	}
+	return
+}
+
+// 遍历, 回调函数里面不能修改hash表
+func (h *HashMap[K, V]) Range(callback func(k K, v V) bool) {
+	for _, t := range []*table[K, V]{&h.old, &h.cur} {
+		for i := range t.slots {
+			s := &t.slots[i]
+			if s.dist != 0 && !callback(s.key, s.val) {
+				return
+			}
+		}
+	}
+}
+
+// 元素个数
+func (h *HashMap[K, V]) Len() int {
+	return h.cur.used + h.old.used
+}
@@ -0,0 +1,166 @@
+package robinhood
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+	"testing"
+
+	"github.com/antlabs/gstl/cmap"
+	"github.com/antlabs/gstl/rhashmap"
+)
+
+// 100w
+// goos: linux
+// goarch: amd64
+// pkg: github.com/antlabs/gstl/robinhood
+// BenchmarkGet               	 1000000	        54.81 ns/op	       0 B/op	       0 allocs/op
+// BenchmarkGetRhashmap       	 1000000	        76.07 ns/op	       0 B/op	       0 allocs/op
+// BenchmarkGetCmap           	 1000000	       259.0 ns/op	       0 B/op	       0 allocs/op
+// BenchmarkGetStd            	 1000000	       244.1 ns/op	       0 B/op	       0 allocs/op
+// BenchmarkSetDelete         	 1000000	        63.46 ns/op	       0 B/op	       0 allocs/op
+// BenchmarkSetDeleteRhashmap 	 1000000	        81.70 ns/op	      24 B/op	       1 allocs/op
+// BenchmarkSetDeleteStd      	 1000000	        60.55 ns/op	       0 B/op	       0 allocs/op
+
+func BenchmarkGet(b *testing.B) {
+	max := float64(b.N)
+	set := NewWithOpt[float64, float64](WithCap(int(max)))
+	for i := 0.0; i < max; i++ {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set.Get(i)
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+func BenchmarkGetRhashmap(b *testing.B) {
+	max := float64(b.N)
+	set := rhashmap.NewWithOpt[float64, float64](rhashmap.WithCap(int(max)))
+	for i := 0.0; i < max; i++ {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set.Get(i)
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+func BenchmarkGetCmap(b *testing.B) {
+	max := float64(b.N)
+	set := cmap.New[float64, float64]()
+	for i := 0.0; i < max; i++ {
+		set.Store(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v, _ := set.Load(i)
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+func BenchmarkGetStd(b *testing.B) {
+	max := float64(b.N)
+	set := make(map[float64]float64, int(max))
+	for i := 0.0; i < max; i++ {
+		set[i] = i
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set[i]
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+// 不预分配, 包含扩容的开销
+func BenchmarkSet(b *testing.B) {
+	max := float64(b.N)
+	set := New[float64, float64]()
+	for i := 0.0; i < max; i++ {
+		set.Set(i, i)
+	}
+}
+
+func BenchmarkSetRhashmap(b *testing.B) {
+	max := float64(b.N)
+	set := rhashmap.New[float64, float64]()
+	for i := 0.0; i < max; i++ {
+		set.Set(i, i)
+	}
+}
+
+func BenchmarkSetCmap(b *testing.B) {
+	max := float64(b.N)
+	set := cmap.New[float64, float64]()
+	for i := 0.0; i < max; i++ {
+		set.Store(i, i)
+	}
+}
+
+func BenchmarkSetStd(b *testing.B) {
+	max := float64(b.N)
+	set := make(map[float64]float64)
+	for i := 0.0; i < max; i++ {
+		set[i] = i
+	}
+}
+
+// 删除之后再插入, 开放寻址不需要墓碑, 表不会越来越脏
+func BenchmarkSetDelete(b *testing.B) {
+	set := New[int, int]()
+	for i := 0; i < 1024; i++ {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		set.Delete(i)
+		set.Set(i+1024, i)
+	}
+}
+
+func BenchmarkSetDeleteRhashmap(b *testing.B) {
+	set := rhashmap.New[int, int]()
+	for i := 0; i < 1024; i++ {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		set.Delete(i)
+		set.Set(i+1024, i)
+	}
+}
+
+func BenchmarkSetDeleteStd(b *testing.B) {
+	set := make(map[int]int)
+	for i := 0; i < 1024; i++ {
+		set[i] = i
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		delete(set, i)
+		set[i+1024] = i
+	}
+}
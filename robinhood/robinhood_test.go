@@ -0,0 +1,186 @@
+package robinhood
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 检查每个元素的dist都和它的位置一致, 并且能找到
+func checkTable(t *testing.T, h *HashMap[int, int], tb *table[int, int]) {
+	used := 0
+	for i, s := range tb.slots {
+		if s.dist == 0 {
+			continue
+		}
+		used++
+		home := s.hash & tb.mask
+		if want := uint32((uint64(i)-home)&tb.mask) + 1; s.dist != want {
+			t.Fatalf("slot %d dist %d, want %d", i, s.dist, want)
+		}
+		if tb.find(s.hash, s.key) != i {
+			t.Fatalf("key %d not found at %d", s.key, i)
+		}
+	}
+	if used != tb.used {
+		t.Fatalf("used %d, want %d", tb.used, used)
+	}
+}
+
+func Test_SetGet(t *testing.T) {
+	hm := New[string, string]()
+	hm.Set("hello", "hello")
+	hm.Set("world", "world")
+
+	if hm.Get("hello") != "hello" || hm.Get("world") != "world" {
+		t.Fatalf("got %q %q", hm.Get("hello"), hm.Get("world"))
+	}
+	if _, ok := hm.TryGet("ni"); ok {
+		t.Fatal("ni found")
+	}
+	if prev, ok := hm.Swap("hello", "hao"); !ok || prev != "hello" || hm.Get("hello") != "hao" {
+		t.Fatalf("swap got %q %v", prev, ok)
+	}
+	if hm.Len() != 2 {
+		t.Fatalf("len %d", hm.Len())
+	}
+}
+
+func Test_SetGet_Lazyinit(t *testing.T) {
+	var hm HashMap[int, string]
+	hm.Delete(1)
+	hm.Set(1, "hello")
+	if hm.Get(1) != "hello" {
+		t.Fatalf("got %q", hm.Get(1))
+	}
+}
+
+func Test_Random(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithCap(1000)}} {
+		hm := NewWithOpt[int, int](opts...)
+		want := make(map[int]int)
+		r := rand.New(rand.NewSource(1))
+
+		for i := 0; i < 50000; i++ {
+			k := r.Intn(5000)
+			switch r.Intn(3) {
+			case 0, 1:
+				prev, replaced := hm.Swap(k, i)
+				old, ok := want[k]
+				if replaced != ok || prev != old {
+					t.Fatalf("swap %d got (%d, %v), want (%d, %v)", k, prev, replaced, old, ok)
+				}
+				want[k] = i
+			case 2:
+				prev, deleted := hm.DeleteWithPrev(k)
+				old, ok := want[k]
+				if deleted != ok || prev != old {
+					t.Fatalf("delete %d got (%d, %v), want (%d, %v)", k, prev, deleted, old, ok)
+				}
+				delete(want, k)
+			}
+
+			k = r.Intn(5000)
+			v, ok := hm.TryGet(k)
+			if old, has := want[k]; ok != has || v != old {
+				t.Fatalf("get %d got (%d, %v)", k, v, ok)
+			}
+			if i%5000 == 0 {
+				checkTable(t, hm, &hm.cur)
+				checkTable(t, hm, &hm.old)
+			}
+		}
+
+		if hm.Len() != len(want) {
+			t.Fatalf("len %d, want %d", hm.Len(), len(want))
+		}
+		got := make(map[int]int)
+		hm.Range(func(k, v int) bool {
+			got[k] = v
+			return true
+		})
+		if len(got) != len(want) {
+			t.Fatalf("range visited %d, want %d", len(got), len(want))
+		}
+		for k, v := range want {
+			if got[k] != v {
+				t.Fatalf("range %d got %d, want %d", k, got[k], v)
+			}
+		}
+	}
+}
+
+// 扩容过程中的读写删
+func Test_Growing(t *testing.T) {
+	hm := New[int, int]()
+	for i := 0; i < 7; i++ {
+		hm.Set(i, i)
+	}
+	hm.Set(7, 7)
+	if !hm.isGrowing() {
+		t.Fatal("not growing")
+	}
+
+	for i := 0; i < 8; i++ {
+		if v, ok := hm.TryGet(i); !ok || v != i {
+			t.Fatalf("get %d got (%d, %v)", i, v, ok)
+		}
+	}
+	hm.Delete(3)
+	hm.Set(0, 100)
+
+	var keys []int
+	hm.Range(func(k, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Ints(keys)
+	if want := []int{0, 1, 2, 4, 5, 6, 7}; !slicesEqual(keys, want) {
+		t.Fatalf("range got %v, want %v", keys, want)
+	}
+	if hm.Get(0) != 100 {
+		t.Fatalf("get 0 got %d", hm.Get(0))
+	}
+
+	for i := 8; i < 100; i++ {
+		hm.Set(i, i)
+	}
+	if hm.Len() != 99 {
+		t.Fatalf("len %d", hm.Len())
+	}
+	checkTable(t, hm, &hm.cur)
+	checkTable(t, hm, &hm.old)
+}
+
+func slicesEqual[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type userKey struct {
+	Name string
+	Age  int8
+	ID   int64
+}
+
+// 结构体里面有string, 相等的key内存不同也要能找到
+func Test_StructKey(t *testing.T) {
+	hm := New[userKey, int]()
+	for i := 0; i < 1000; i++ {
+		hm.Set(userKey{Name: strings.Repeat("a", i%10), Age: int8(i), ID: int64(i)}, i)
+	}
+	for i := 0; i < 1000; i++ {
+		k := userKey{Name: strings.Repeat("a", i%10), Age: int8(i), ID: int64(i)}
+		if v, ok := hm.TryGet(k); !ok || v != i {
+			t.Fatalf("get %v got (%d, %v)", k, v, ok)
+		}
+	}
+}
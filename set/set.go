@@ -2,8 +2,11 @@ package set
 
 // apache 2.0 antlabs
 import (
+	"math/bits"
+
 	"github.com/antlabs/gstl/api"
 	"github.com/antlabs/gstl/rbtree"
+	"github.com/antlabs/gstl/sortedops"
 	"golang.org/x/exp/constraints"
 )
 
@@ -62,52 +65,105 @@ func (s *Set[K]) IsMember(k K) (b bool) {
 	return
 }
 
-// 返回的是s1没有的元素, s - s1
-func (s *Set[K]) Diff(s1 *Set[K]) (new *Set[K]) {
+// keys是严格升序的, 直接O(n)建树
+func fromSorted[K constraints.Ordered](keys []K) *Set[K] {
+	t, err := rbtree.FromSortedFunc(len(keys), func(i int) (K, struct{}) {
+		return keys[i], struct{}{}
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+	return &Set[K]{SortedMap: t}
+}
 
-	new = New[K]()
-	s.Range(func(k K) bool {
-		if !s1.IsMember(k) {
-			new.Set(k)
+// 收集升序的结果
+func appendKey[K constraints.Ordered](keys *[]K) func(k K, _ struct{}) {
+	return func(k K, _ struct{}) {
+		*keys = append(*keys, k)
+	}
+}
+
+// 合并两个升序的slice, 去掉重复的key
+func mergeKeys[K constraints.Ordered](a, b []K) []K {
+	rv := make([]K, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			rv = append(rv, a[i])
+			i++
+		case a[i] > b[j]:
+			rv = append(rv, b[j])
+			j++
+		default:
+			rv = append(rv, a[i])
+			i++
+			j++
 		}
-		return true
-	})
-	return
+	}
+	rv = append(rv, a[i:]...)
+	return append(rv, b[j:]...)
 }
 
-// 返回两个集合的所有元素
-func (s *Set[K]) Union(sets ...*Set[K]) (new *Set[K]) {
+// 返回的是s1没有的元素, s - s1
+func (s *Set[K]) Diff(s1 *Set[K]) (new *Set[K]) {
+	var keys []K
+	sortedops.DifferenceEach[K, struct{}](s.SortedMap, s1.SortedMap, appendKey(&keys))
+	return fromSorted(keys)
+}
 
-	new = New[K]()
-	s.Range(func(k K) bool {
-		new.Set(k)
-		return true
-	})
+// 返回只在一个集合里出现的元素
+func (s *Set[K]) SymmetricDiff(s1 *Set[K]) (new *Set[K]) {
+	var keys []K
+	sortedops.SymmetricDifferenceEach[K, struct{}](s.SortedMap, s1.SortedMap, appendKey(&keys))
+	return fromSorted(keys)
+}
 
+// 返回所有集合的所有元素
+// 每个集合先转成升序的slice, 两两合并, 最后一次性建树, O(n*log(k)), k是集合的个数
+func (s *Set[K]) Union(sets ...*Set[K]) (new *Set[K]) {
+	all := make([][]K, 0, len(sets)+1)
+	all = append(all, s.ToSlice())
 	for _, s1 := range sets {
-		s1.Range(func(k K) bool {
-			new.Set(k)
-			return true
-		})
+		all = append(all, s1.ToSlice())
 	}
 
-	return
+	for len(all) > 1 {
+		next := all[:0]
+		for i := 0; i < len(all); i += 2 {
+			if i+1 == len(all) {
+				next = append(next, all[i])
+				break
+			}
+			next = append(next, mergeKeys(all[i], all[i+1]))
+		}
+		all = next
+	}
+	return fromSorted(all[0])
 }
 
 // 返回两个集合的公共集合
+// 大小差不多时同时遍历两边, O(n+m)
+// 差得很多时遍历小的集合去大的集合里查, O(min*log(max))
 func (s *Set[K]) Intersection(s1 *Set[K]) (new *Set[K]) {
-	if s.Len() >= s1.Len() {
-		s, s1 = s1, s
+	small, large := s, s1
+	if small.Len() > large.Len() {
+		small, large = large, small
 	}
 
-	new = New[K]()
-	s.Range(func(k K) bool {
-		if s1.IsMember(k) {
-			new.Set(k)
-		}
-		return true
-	})
-	return
+	var keys []K
+	if small.Len()*bits.Len(uint(large.Len())) < small.Len()+large.Len() {
+		small.Range(func(k K) bool {
+			if large.IsMember(k) {
+				keys = append(keys, k)
+			}
+			return true
+		})
+		return fromSorted(keys)
+	}
+
+	sortedops.IntersectEach[K, struct{}](s.SortedMap, s1.SortedMap, nil, appendKey(&keys))
+	return fromSorted(keys)
 }
 
 // 测试集合s每个元素是否在s1里面, s <= s1
@@ -150,6 +150,17 @@ func Test_Diff(t *testing.T) {
 	}
 }
 
+func Test_SymmetricDiff(t *testing.T) {
+	s := From("1234", "5678", "9abc")
+	s2 := From("abcde", "5678", "9abc")
+
+	v := s.SymmetricDiff(s2).ToSlice()
+	expected := []string{"1234", "abcde"}
+	if !equalSlices(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+}
+
 func Test_Intersection(t *testing.T) {
 	s := From("1234", "5678", "9abc")
 	s2 := From("abcde", "5678", "9abc")
@@ -161,6 +172,46 @@ func Test_Intersection(t *testing.T) {
 	}
 }
 
+// 多个集合有重叠, 结果去重并且升序
+func Test_Union_Overlap(t *testing.T) {
+	s := From("a", "c", "e")
+	sets := []*Set[string]{From("b", "c"), From("a", "f"), New[string](), From("d", "e", "f")}
+
+	v := s.Union(sets...).ToSlice()
+	expected := []string{"a", "b", "c", "d", "e", "f"}
+	if !equalSlices(v, expected) {
+		t.Errorf("expected %v, got %v", expected, v)
+	}
+
+	// 没有参数时返回一份拷贝
+	u := s.Union()
+	u.Set("z")
+	if s.IsMember("z") {
+		t.Errorf("Union() should return a copy")
+	}
+}
+
+// 大小差很多的集合走查找的路径, 结果和小集合在前在后无关
+func Test_Intersection_Uneven(t *testing.T) {
+	large := New[int]()
+	for i := 0; i < 10000; i++ {
+		large.Set(i * 2)
+	}
+	small := From(-1, 4, 7, 100, 19998, 20000)
+
+	expected := []int{4, 100, 19998}
+	for _, v := range [][]int{small.Intersection(large).ToSlice(), large.Intersection(small).ToSlice()} {
+		if len(v) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, v)
+		}
+		for i := range v {
+			if v[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, v)
+			}
+		}
+	}
+}
+
 func Test_IsSubset(t *testing.T) {
 	s := From("5678", "9abc")
 	s2 := From("abcde", "5678", "9abc")
@@ -0,0 +1,80 @@
+package skiplist
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// record的字段, 从上到下是每一层, 最后是节点的内容
+func levelFields(level int, label string) string {
+	fields := make([]string, 0, level+1)
+	for l := level - 1; l >= 0; l-- {
+		fields = append(fields, fmt.Sprintf("<l%d> L%d", l, l))
+	}
+	fields = append(fields, label)
+	return strings.Join(fields, "|")
+}
+
+// 输出graphviz dot格式, 每个节点按层画出前进指针
+// opts.MaxDepth表示最多输出多少个节点
+func (s *SkipList[K, T]) WriteDOT(w io.Writer, opts *dot.Options[K, T]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph skiplist {")
+	dw.Printf("\trankdir=LR;")
+	dw.Printf("\tnode [shape=record];")
+	dw.Printf("\thead [label=\"%s\"];", levelFields(s.level, "head"))
+
+	ids := map[*Node[K, T]]string{s.head: "head"}
+	ellipsis := ""
+	depth := 0
+	for x := s.head.NodeLevel[0].forward; x != nil; x = x.NodeLevel[0].forward {
+		if dw.Truncated(depth) {
+			ellipsis = dw.Ellipsis()
+			break
+		}
+
+		id := dw.NextID()
+		ids[x] = id
+		dw.Printf("\t%s [label=\"%s\"];", id, levelFields(len(x.NodeLevel), idot.EscapeRecord(dw.Label(x.score, x.elem))))
+		depth++
+	}
+
+	for x := s.head; x != nil; x = x.NodeLevel[0].forward {
+		id, ok := ids[x]
+		if !ok {
+			break
+		}
+
+		level := len(x.NodeLevel)
+		if x == s.head {
+			level = s.level
+		}
+		for l := 0; l < level; l++ {
+			f := x.NodeLevel[l].forward
+			if f == nil {
+				continue
+			}
+
+			if fid, ok := ids[f]; ok {
+				dw.Printf("\t%s:l%d -> %s:l%d;", id, l, fid, l)
+			} else {
+				dw.Printf("\t%s:l%d -> %s;", id, l, ellipsis)
+			}
+		}
+	}
+
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 加读锁输出graphviz dot格式
+func (c *ConcurrentSkipList[K, T]) WriteDOT(w io.Writer, opts *dot.Options[K, T]) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SkipList.WriteDOT(w, opts)
+}
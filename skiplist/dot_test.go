@@ -0,0 +1,41 @@
+package skiplist
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+func Test_SkipList_WriteDOT(t *testing.T) {
+	s := New[int, int]()
+	s.InsertInner(1, 1, 1)
+	s.InsertInner(2, 2, 3)
+	s.InsertInner(3, 3, 2)
+
+	var buf bytes.Buffer
+	if err := s.WriteDOT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph skiplist {") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	// 第0层3条边, 第1层2条边, 第2层1条边
+	if n := strings.Count(out, "->"); n != 6 {
+		t.Fatalf("got %d edges:\n%s", n, out)
+	}
+	if !strings.Contains(out, "head:l2 -> n2:l2") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := s.WriteDOT(&buf, &dot.Options[int, int]{MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"..."`) || strings.Contains(buf.String(), `L0|2"`) {
+		t.Fatalf("bad output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,77 @@
+package skiplist
+
+// apache 2.0 antlabs
+import (
+	"github.com/antlabs/gstl/internal/bulk"
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	ErrNotSorted    = bulk.ErrNotSorted
+	ErrDuplicateKey = bulk.ErrDuplicateKey
+	ErrLenMismatch  = bulk.ErrLenMismatch
+)
+
+// 从升序的keys, vals构造skiplist, O(n)
+// keys不是升序或者有重复的key会返回错误
+func FromSorted[K constraints.Ordered, T any](keys []K, vals []T) (*SkipList[K, T], error) {
+	f, err := bulk.Slices(keys, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromSortedFunc(len(keys), f)
+}
+
+// 从升序的n个元素构造skiplist, O(n), f按0到n-1的顺序调用
+// key不是升序或者有重复的key会返回错误, 发现错误之后不会再调用f
+func FromSortedFunc[K constraints.Ordered, T any](n int, f func(i int) (K, T)) (*SkipList[K, T], error) {
+	s := New[K, T]()
+	if n <= 0 {
+		return s, nil
+	}
+
+	var (
+		// 每一层最后一个节点, 和它的排名
+		update [SKIPLIST_MAXLEVEL]*Node[K, T]
+		rank   [SKIPLIST_MAXLEVEL]int
+	)
+	for i := range update {
+		update[i] = s.head
+	}
+
+	it := bulk.NewIter(f)
+	var prev *Node[K, T]
+	for i := 0; i < n; i++ {
+		score, elem := it.Next()
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+
+		level := s.rand()
+		if level > s.level {
+			s.level = level
+		}
+
+		// 新节点总是挂在每一层的最后面
+		x := s.newNode(level, score, elem)
+		for l := 0; l < level; l++ {
+			update[l].NodeLevel[l].forward = x
+			update[l].NodeLevel[l].span = i + 1 - rank[l]
+			update[l] = x
+			rank[l] = i + 1
+		}
+
+		x.backward = prev
+		prev = x
+	}
+
+	// 每一层最后一个节点的span是到结尾的距离, 和InsertInner保持一致
+	for l := 0; l < s.level; l++ {
+		update[l].NodeLevel[l].span = n - rank[l]
+	}
+
+	s.tail = prev
+	s.length = n
+	return s, nil
+}
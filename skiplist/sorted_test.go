@@ -0,0 +1,109 @@
+package skiplist
+
+import (
+	"testing"
+)
+
+// 检查每一层的span和backward指针
+func checkSpan(t *testing.T, s *SkipList[int, int]) {
+	rank := make(map[*Node[int, int]]int)
+	var prev *Node[int, int]
+	i := 0
+	for x := s.head.NodeLevel[0].forward; x != nil; x = x.NodeLevel[0].forward {
+		i++
+		rank[x] = i
+		if x.backward != prev {
+			t.Fatalf("node %d: wrong backward", x.score)
+		}
+		prev = x
+	}
+	if s.tail != prev {
+		t.Fatal("wrong tail")
+	}
+
+	for l := 0; l < s.level; l++ {
+		pos := 0
+		x := s.head
+		for x.NodeLevel[l].forward != nil {
+			pos += x.NodeLevel[l].span
+			x = x.NodeLevel[l].forward
+			if rank[x] != pos {
+				t.Fatalf("level %d, node %d: rank %d, span sum %d", l, x.score, rank[x], pos)
+			}
+		}
+		if pos+x.NodeLevel[l].span != s.Len() {
+			t.Fatalf("level %d: span sum %d, len %d", l, pos+x.NodeLevel[l].span, s.Len())
+		}
+	}
+}
+
+func Test_SkipList_FromSorted(t *testing.T) {
+	for n := 0; n < 300; n++ {
+		keys := make([]int, n)
+		vals := make([]int, n)
+		for i := range keys {
+			keys[i] = i * 2
+			vals[i] = i
+		}
+
+		s, err := FromSorted(keys, vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Len() != n {
+			t.Fatalf("n:%d, len:%d", n, s.Len())
+		}
+		checkSpan(t, s)
+
+		i := 0
+		s.Range(func(k, v int) bool {
+			if k != keys[i] || v != vals[i] {
+				t.Fatalf("n:%d, index %d: got (%d, %d)", n, i, k, v)
+			}
+			i++
+			return true
+		})
+
+		if n == 0 {
+			continue
+		}
+
+		// 构造好的skiplist可以继续修改
+		s.Set(1, 1)
+		s.Delete(0)
+		checkSpan(t, s)
+		if _, ok := s.TryGet(1); !ok || s.Len() != n {
+			t.Fatalf("n:%d, set/delete after FromSorted failed", n)
+		}
+	}
+}
+
+func Test_SkipList_FromSorted_Error(t *testing.T) {
+	if _, err := FromSorted([]int{1, 3, 2}, []int{1, 2, 3}); err != ErrNotSorted {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2, 2}, []int{1, 2, 3}); err != ErrDuplicateKey {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := FromSorted([]int{1, 2}, []int{1}); err != ErrLenMismatch {
+		t.Fatalf("got %v", err)
+	}
+
+	s, err := FromSortedFunc(100, func(i int) (int, string) { return i, "" })
+	if err != nil || s.Len() != 100 {
+		t.Fatalf("len:%d, err:%v", s.Len(), err)
+	}
+
+	// 发现乱序之后不再调用f
+	calls := 0
+	_, err = FromSortedFunc(100, func(i int) (int, int) {
+		calls++
+		if i == 10 {
+			return 0, i
+		}
+		return i, i
+	})
+	if err != ErrNotSorted || calls != 11 {
+		t.Fatalf("err:%v, calls:%d", err, calls)
+	}
+}
@@ -0,0 +1,71 @@
+package skiplist
+
+// apache 2.0 antlabs
+import (
+	"fmt"
+)
+
+// 检查每一层的顺序, span, backward指针, tail和Len(), 有问题返回错误
+func (s *SkipList[K, T]) Validate() error {
+	if s.level < 1 || s.level > SKIPLIST_MAXLEVEL {
+		return fmt.Errorf("skiplist: invalid level %d", s.level)
+	}
+	for l := s.level; l < len(s.head.NodeLevel); l++ {
+		if s.head.NodeLevel[l].forward != nil {
+			return fmt.Errorf("skiplist: head has forward pointer at level %d above %d", l, s.level)
+		}
+	}
+
+	// 第0层, 记录每个节点的排名, 从1开始
+	rank := make(map[*Node[K, T]]int, s.length)
+	var prev *Node[K, T]
+	for x := s.head.NodeLevel[0].forward; x != nil; x = x.NodeLevel[0].forward {
+		if len(x.NodeLevel) < 1 || len(x.NodeLevel) > s.level {
+			return fmt.Errorf("skiplist: key %v has %d levels, list level is %d", x.score, len(x.NodeLevel), s.level)
+		}
+		if prev != nil && x.score <= prev.score {
+			return fmt.Errorf("skiplist: key %v after %v is out of order", x.score, prev.score)
+		}
+		if x.backward != prev {
+			return fmt.Errorf("skiplist: key %v has wrong backward pointer", x.score)
+		}
+
+		rank[x] = len(rank) + 1
+		prev = x
+	}
+
+	if s.tail != prev {
+		return fmt.Errorf("skiplist: wrong tail")
+	}
+	if len(rank) != s.length {
+		return fmt.Errorf("skiplist: Len() is %d, but has %d nodes", s.length, len(rank))
+	}
+
+	// 每一层的链表都是第0层的子序列, span是两个节点排名的差
+	for l := 0; l < s.level; l++ {
+		want := 0
+		for x := range rank {
+			if len(x.NodeLevel) > l {
+				want++
+			}
+		}
+
+		got, pos := 0, 0
+		x := s.head
+		for ; x.NodeLevel[l].forward != nil; got++ {
+			pos += x.NodeLevel[l].span
+			x = x.NodeLevel[l].forward
+			if rank[x] != pos {
+				return fmt.Errorf("skiplist: key %v at level %d has rank %d, span sum is %d", x.score, l, rank[x], pos)
+			}
+		}
+
+		if got != want {
+			return fmt.Errorf("skiplist: level %d has %d nodes, want %d", l, got, want)
+		}
+		if pos+x.NodeLevel[l].span != s.length {
+			return fmt.Errorf("skiplist: level %d span sum is %d, want %d", l, pos+x.NodeLevel[l].span, s.length)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package sortedops
+
+// apache 2.0 antlabs
+
+// 两个有序的map做集合运算, 按顺序同时遍历两边(merge join), 遍历是O(n+m)
+// api.SortedMap只有回调形式的Range, 没法同时走两个迭代器, 所以先把b拷贝到slice里面, 额外占用O(m)的内存
+// Union, Intersect这些函数每个结果都调用一次dst.Set, dst是树的时候整体是O((n+m)*log(n+m))
+// 需要严格O(n+m)时用UnionEach这些函数, 结果按key升序给出, 再用FromSorted一次性建树
+// dst不能是a或者b本身, 遍历的同时修改会破坏遍历
+import (
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+// 两边都有的key, 用返回值作为结果的值, 为nil时使用a的值
+type ConflictFunc[K constraints.Ordered, V any] func(k K, a, b V) V
+
+type pair[K constraints.Ordered, V any] struct {
+	key K
+	val V
+}
+
+func collect[K constraints.Ordered, V any](m api.SortedMap[K, V]) []pair[K, V] {
+	s := make([]pair[K, V], 0, m.Len())
+	m.Range(func(k K, v V) bool {
+		s = append(s, pair[K, V]{key: k, val: v})
+		return true
+	})
+	return s
+}
+
+// 按key的顺序同时遍历a和b, 只在a里, 只在b里, 两边都有的key分别调用对应的回调
+func merge[K constraints.Ordered, V any](a, b api.SortedMap[K, V], onlyA, onlyB func(k K, v V), both func(k K, va, vb V)) {
+	bs := collect(b)
+	i := 0
+	a.Range(func(k K, v V) bool {
+		for ; i < len(bs) && bs[i].key < k; i++ {
+			onlyB(bs[i].key, bs[i].val)
+		}
+
+		if i < len(bs) && bs[i].key == k {
+			both(k, v, bs[i].val)
+			i++
+		} else {
+			onlyA(k, v)
+		}
+		return true
+	})
+
+	for ; i < len(bs); i++ {
+		onlyB(bs[i].key, bs[i].val)
+	}
+}
+
+func resolve[K constraints.Ordered, V any](conflict ConflictFunc[K, V], k K, a, b V) V {
+	if conflict == nil {
+		return a
+	}
+	return conflict(k, a, b)
+}
+
+// 并集, 按key升序调用emit
+func UnionEach[K constraints.Ordered, V any](a, b api.SortedMap[K, V], conflict ConflictFunc[K, V], emit func(k K, v V)) {
+	merge(a, b, emit, emit, func(k K, va, vb V) {
+		emit(k, resolve(conflict, k, va, vb))
+	})
+}
+
+// 交集, 按key升序调用emit
+func IntersectEach[K constraints.Ordered, V any](a, b api.SortedMap[K, V], conflict ConflictFunc[K, V], emit func(k K, v V)) {
+	skip := func(k K, v V) {}
+	merge(a, b, skip, skip, func(k K, va, vb V) {
+		emit(k, resolve(conflict, k, va, vb))
+	})
+}
+
+// 差集a-b, 按key升序调用emit
+func DifferenceEach[K constraints.Ordered, V any](a, b api.SortedMap[K, V], emit func(k K, v V)) {
+	merge(a, b, emit, func(k K, v V) {}, func(k K, va, vb V) {})
+}
+
+// 对称差集, 只在一边出现的key, 按key升序调用emit
+func SymmetricDifferenceEach[K constraints.Ordered, V any](a, b api.SortedMap[K, V], emit func(k K, v V)) {
+	merge(a, b, emit, emit, func(k K, va, vb V) {})
+}
+
+// 并集, 结果写到dst, dst不能是a或者b
+func Union[K constraints.Ordered, V any](dst api.Map[K, V], a, b api.SortedMap[K, V], conflict ConflictFunc[K, V]) {
+	UnionEach(a, b, conflict, dst.Set)
+}
+
+// 交集, 结果写到dst, dst不能是a或者b
+func Intersect[K constraints.Ordered, V any](dst api.Map[K, V], a, b api.SortedMap[K, V], conflict ConflictFunc[K, V]) {
+	IntersectEach(a, b, conflict, dst.Set)
+}
+
+// 差集a-b, 结果写到dst, dst不能是a或者b
+func Difference[K constraints.Ordered, V any](dst api.Map[K, V], a, b api.SortedMap[K, V]) {
+	DifferenceEach(a, b, dst.Set)
+}
+
+// 对称差集, 只在一边出现的key, 结果写到dst, dst不能是a或者b
+func SymmetricDifference[K constraints.Ordered, V any](dst api.Map[K, V], a, b api.SortedMap[K, V]) {
+	SymmetricDifferenceEach(a, b, dst.Set)
+}
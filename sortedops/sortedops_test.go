@@ -0,0 +1,142 @@
+package sortedops
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/antlabs/gstl/api"
+	"github.com/antlabs/gstl/btree"
+	"github.com/antlabs/gstl/rbtree"
+	"github.com/antlabs/gstl/skiplist"
+)
+
+func randMap(r *rand.Rand, m api.SortedMap[int, int], n int) map[int]int {
+	model := make(map[int]int)
+	for i := 0; i < n; i++ {
+		k, v := r.Intn(200), r.Int()
+		m.Set(k, v)
+		model[k] = v
+	}
+	return model
+}
+
+// dst按key的顺序和model对比
+func checkMap(t *testing.T, dst api.SortedMap[int, int], model map[int]int) {
+	t.Helper()
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	if dst.Len() != len(keys) {
+		t.Fatalf("len:%d, want:%d", dst.Len(), len(keys))
+	}
+
+	i := 0
+	dst.Range(func(k, v int) bool {
+		if k != keys[i] || v != model[k] {
+			t.Fatalf("index %d: got (%d, %d), want (%d, %d)", i, k, v, keys[i], model[keys[i]])
+		}
+		i++
+		return true
+	})
+}
+
+func Test_SortedOps(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sum := func(k, a, b int) int { return a + b }
+
+	for i := 0; i < 100; i++ {
+		a, b := rbtree.New[int, int](), skiplist.New[int, int]()
+		am, bm := randMap(r, a, r.Intn(150)), randMap(r, b, r.Intn(150))
+
+		union, inter, diff, sym := map[int]int{}, map[int]int{}, map[int]int{}, map[int]int{}
+		for k, v := range am {
+			if bv, ok := bm[k]; ok {
+				union[k] = v + bv
+				inter[k] = v
+			} else {
+				union[k] = v
+				diff[k] = v
+				sym[k] = v
+			}
+		}
+		for k, v := range bm {
+			if _, ok := am[k]; !ok {
+				union[k] = v
+				sym[k] = v
+			}
+		}
+
+		dst := btree.New[int, int](2)
+		Union[int, int](dst, a, b, sum)
+		checkMap(t, dst, union)
+
+		dst = btree.New[int, int](2)
+		Intersect[int, int](dst, a, b, nil)
+		checkMap(t, dst, inter)
+
+		dst = btree.New[int, int](2)
+		Difference[int, int](dst, a, b)
+		checkMap(t, dst, diff)
+
+		dst = btree.New[int, int](2)
+		SymmetricDifference[int, int](dst, a, b)
+		checkMap(t, dst, sym)
+	}
+}
+
+func Test_Intersect_Conflict(t *testing.T) {
+	a, b := rbtree.New[string, int](), rbtree.New[string, int]()
+	a.Set("x", 1)
+	a.Set("y", 2)
+	b.Set("y", 20)
+	b.Set("z", 30)
+
+	dst := rbtree.New[string, int]()
+	Intersect[string, int](dst, a, b, func(k string, va, vb int) int { return vb })
+	if dst.Len() != 1 || dst.Get("y") != 20 {
+		t.Fatalf("len:%d, y:%d", dst.Len(), dst.Get("y"))
+	}
+}
+
+// Each版本按key升序给出结果, 可以直接用FromSorted建树
+func Test_SortedOps_Each(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 50; i++ {
+		a, b := rbtree.New[int, int](), btree.New[int, int](2)
+		randMap(r, a, r.Intn(150))
+		randMap(r, b, r.Intn(150))
+
+		for _, op := range []struct {
+			each func(emit func(k, v int))
+			set  func(dst api.Map[int, int])
+		}{
+			{func(emit func(k, v int)) { UnionEach[int, int](a, b, nil, emit) }, func(dst api.Map[int, int]) { Union[int, int](dst, a, b, nil) }},
+			{func(emit func(k, v int)) { IntersectEach[int, int](a, b, nil, emit) }, func(dst api.Map[int, int]) { Intersect[int, int](dst, a, b, nil) }},
+			{func(emit func(k, v int)) { DifferenceEach[int, int](a, b, emit) }, func(dst api.Map[int, int]) { Difference[int, int](dst, a, b) }},
+			{func(emit func(k, v int)) { SymmetricDifferenceEach[int, int](a, b, emit) }, func(dst api.Map[int, int]) { SymmetricDifference[int, int](dst, a, b) }},
+		} {
+			var keys, vals []int
+			op.each(func(k, v int) {
+				keys = append(keys, k)
+				vals = append(vals, v)
+			})
+			got, err := rbtree.FromSorted(keys, vals)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			need := rbtree.New[int, int]()
+			op.set(need)
+			model := make(map[int]int)
+			need.Range(func(k, v int) bool {
+				model[k] = v
+				return true
+			})
+			checkMap(t, got, model)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package splaytree
+
+// apache 2.0 antlabs
+
+// 伸展树, 每次访问都会把节点旋转到根, 热点key离根更近
+// 参考资料
+// https://www.cs.cmu.edu/~sleator/papers/self-adjusting.pdf
+// Get也会修改树的结构, 多个goroutine同时读也要加锁
+import (
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*SplayTree[int, int])(nil)
+
+// 元素
+type pair[K constraints.Ordered, V any] struct {
+	val V
+	key K
+}
+
+type node[K constraints.Ordered, V any] struct {
+	left  *node[K, V]
+	right *node[K, V]
+	pair[K, V]
+}
+
+// 伸展树的结构
+type SplayTree[K constraints.Ordered, V any] struct {
+	length int
+	root   *node[K, V]
+}
+
+// 构造函数
+func New[K constraints.Ordered, V any]() *SplayTree[K, V] {
+	return &SplayTree[K, V]{}
+}
+
+// 自顶向下的splay, 把k或者最后访问的节点(k的前驱或者后继)旋转到根
+func (s *SplayTree[K, V]) splay(k K) {
+	n := s.root
+	if n == nil {
+		return
+	}
+
+	// header.right是左树, header.left是右树
+	var header node[K, V]
+	l, r := &header, &header
+	for {
+		if k < n.key {
+			if n.left == nil {
+				break
+			}
+			if k < n.left.key {
+				// zig-zig, 右旋
+				y := n.left
+				n.left = y.right
+				y.right = n
+				n = y
+				if n.left == nil {
+					break
+				}
+			}
+			// 挂到右树
+			r.left = n
+			r = n
+			n = n.left
+		} else if k > n.key {
+			if n.right == nil {
+				break
+			}
+			if k > n.right.key {
+				// zig-zig, 左旋
+				y := n.right
+				n.right = y.left
+				y.left = n
+				n = y
+				if n.right == nil {
+					break
+				}
+			}
+			// 挂到左树
+			l.right = n
+			l = n
+			n = n.right
+		} else {
+			break
+		}
+	}
+
+	l.right = n.left
+	r.left = n.right
+	n.left = header.right
+	n.right = header.left
+	s.root = n
+}
+
+// 第一个节点
+func (s *SplayTree[K, V]) First() (v V, ok bool) {
+	n := s.root
+	if n == nil {
+		return
+	}
+
+	for n.left != nil {
+		n = n.left
+	}
+	s.splay(n.key)
+	return n.val, true
+}
+
+// 最后一个节点
+func (s *SplayTree[K, V]) Last() (v V, ok bool) {
+	n := s.root
+	if n == nil {
+		return
+	}
+
+	for n.right != nil {
+		n = n.right
+	}
+	s.splay(n.key)
+	return n.val, true
+}
+
+// Get
+func (s *SplayTree[K, V]) Get(k K) (v V) {
+	v, _ = s.TryGet(k)
+	return
+}
+
+// 查找k, 找到的节点会变成根
+func (s *SplayTree[K, V]) TryGet(k K) (v V, ok bool) {
+	s.splay(k)
+	if s.root != nil && s.root.key == k {
+		return s.root.val, true
+	}
+	return
+}
+
+func (s *SplayTree[K, V]) Set(k K, v V) {
+	_, _ = s.Swap(k, v)
+}
+
+// 设置接口, 如果有值, 把prev值带返回, 并且被替换, 没有就新加
+func (s *SplayTree[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	s.splay(k)
+	if s.root != nil && s.root.key == k {
+		prev = s.root.val
+		s.root.val = v
+		return prev, true
+	}
+
+	// 新节点做根, 老的根按大小挂到左边或者右边
+	n := &node[K, V]{pair: pair[K, V]{key: k, val: v}}
+	if s.root != nil {
+		if k < s.root.key {
+			n.left = s.root.left
+			n.right = s.root
+			s.root.left = nil
+		} else {
+			n.right = s.root.right
+			n.left = s.root
+			s.root.right = nil
+		}
+	}
+	s.root = n
+	s.length++
+	return
+}
+
+func (s *SplayTree[K, V]) Delete(k K) {
+	s.DeleteWithPrev(k)
+}
+
+// 删除, 返回被删除的值
+func (s *SplayTree[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
+	s.splay(k)
+	if s.root == nil || s.root.key != k {
+		return
+	}
+
+	prev = s.root.val
+	left, right := s.root.left, s.root.right
+	if left == nil {
+		s.root = right
+	} else {
+		// 左子树最大的节点转到根, 它没有右孩子
+		s.root = left
+		s.splay(k)
+		s.root.right = right
+	}
+	s.length--
+	return prev, true
+}
+
+// 遍历, 不会调整树的结构
+// 伸展树可能退化成链表, 所以用栈代替递归
+func (s *SplayTree[K, V]) Range(callback func(k K, v V) bool) {
+	var stack []*node[K, V]
+	n := s.root
+	for n != nil || len(stack) > 0 {
+		for ; n != nil; n = n.left {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !callback(n.key, n.val) {
+			return
+		}
+		n = n.right
+	}
+}
+
+// 倒序遍历
+func (s *SplayTree[K, V]) RangePrev(callback func(k K, v V) bool) {
+	var stack []*node[K, V]
+	n := s.root
+	for n != nil || len(stack) > 0 {
+		for ; n != nil; n = n.right {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !callback(n.key, n.val) {
+			return
+		}
+		n = n.left
+	}
+}
+
+// 返回最大的n个值, 降序返回, 10, 9, 8, 7
+func (s *SplayTree[K, V]) TopMax(limit int, callback func(k K, v V) bool) {
+	s.RangePrev(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+// 返回最小的n个值, 升序返回, 比如0,1,2,3
+func (s *SplayTree[K, V]) TopMin(limit int, callback func(k K, v V) bool) {
+	s.Range(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+func (s *SplayTree[K, V]) Len() int {
+	return s.length
+}
@@ -0,0 +1,80 @@
+package splaytree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func BenchmarkGetAsc(b *testing.B) {
+	max := float64(b.N)
+	set := New[float64, float64]()
+	for i := 0.0; i < max; i++ {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set.Get(i)
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+func BenchmarkGetDesc(b *testing.B) {
+	max := float64(b.N)
+	set := New[float64, float64]()
+	for i := max; i >= 0; i-- {
+		set.Set(i, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set.Get(i)
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
+
+// 90%的访问落在1%的key上, 伸展树的优势场景
+func BenchmarkGetSkewed(b *testing.B) {
+	const n = 100000
+	set := New[int, int]()
+	for i := 0; i < n; i++ {
+		set.Set(i, i)
+	}
+	r := rand.New(rand.NewSource(1))
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		k := r.Intn(n)
+		if r.Intn(10) != 0 {
+			k = r.Intn(n / 100)
+		}
+		if v := set.Get(k); v != k {
+			panic(fmt.Sprintf("need:%d, got:%d", k, v))
+		}
+	}
+}
+
+func BenchmarkGetStd(b *testing.B) {
+	max := float64(b.N)
+	set := make(map[float64]float64, int(max))
+	for i := 0.0; i < max; i++ {
+		set[i] = i
+	}
+
+	b.ResetTimer()
+
+	for i := 0.0; i < max; i++ {
+		v := set[i]
+		if v != i {
+			panic(fmt.Sprintf("need:%f, got:%f", i, v))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package splaytree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// 检查二叉搜索树的顺序, 返回节点个数
+func checkOrder(t *testing.T, n *node[int, int], lo, hi *int) int {
+	if n == nil {
+		return 0
+	}
+	if lo != nil && n.key <= *lo || hi != nil && n.key >= *hi {
+		t.Fatalf("key %d out of order", n.key)
+	}
+	return checkOrder(t, n.left, lo, &n.key) + checkOrder(t, n.right, &n.key, hi) + 1
+}
+
+func Test_SplayTree_Random(t *testing.T) {
+	s := New[int, int]()
+	want := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(2000)
+		switch r.Intn(3) {
+		case 0, 1:
+			prev, replaced := s.Swap(k, i)
+			old, ok := want[k]
+			if replaced != ok || prev != old {
+				t.Fatalf("swap %d got (%d, %v), want (%d, %v)", k, prev, replaced, old, ok)
+			}
+			want[k] = i
+		case 2:
+			prev, deleted := s.DeleteWithPrev(k)
+			old, ok := want[k]
+			if deleted != ok || prev != old {
+				t.Fatalf("delete %d got (%d, %v), want (%d, %v)", k, prev, deleted, old, ok)
+			}
+			delete(want, k)
+		}
+
+		k = r.Intn(2000)
+		v, ok := s.TryGet(k)
+		if old, has := want[k]; ok != has || v != old {
+			t.Fatalf("get %d got (%d, %v)", k, v, ok)
+		}
+	}
+
+	if n := checkOrder(t, s.root, nil, nil); n != s.Len() || n != len(want) {
+		t.Fatalf("nodes %d, len %d, want %d", n, s.Len(), len(want))
+	}
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	i := 0
+	s.Range(func(k, v int) bool {
+		if k != keys[i] || v != want[k] {
+			t.Fatalf("range got %d=%d at %d", k, v, i)
+		}
+		i++
+		return true
+	})
+	if i != len(keys) {
+		t.Fatalf("range visited %d, want %d", i, len(keys))
+	}
+	s.RangePrev(func(k, v int) bool {
+		i--
+		if k != keys[i] {
+			t.Fatalf("range prev got %d, want %d", k, keys[i])
+		}
+		return true
+	})
+}
+
+// 访问过的key会被转到根
+func Test_SplayTree_Splay(t *testing.T) {
+	s := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		s.Set(i, i)
+	}
+
+	for _, k := range []int{500, 3, 999, 0, 500} {
+		if s.Get(k) != k || s.root.key != k {
+			t.Fatalf("get %d, root %d", k, s.root.key)
+		}
+	}
+	if v, ok := s.First(); !ok || v != 0 || s.root.key != 0 {
+		t.Fatalf("first %d", v)
+	}
+	if v, ok := s.Last(); !ok || v != 999 || s.root.key != 999 {
+		t.Fatalf("last %d", v)
+	}
+	checkOrder(t, s.root, nil, nil)
+}
+
+func Test_SplayTree_Top(t *testing.T) {
+	s := New[int, int]()
+	for i := 100; i >= 0; i-- {
+		s.Set(i, i)
+	}
+
+	var got []int
+	s.TopMin(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	s.TopMax(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if want := []int{0, 1, 2, 100, 99, 98}; !equalSlices(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func equalSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,333 @@
+package treap
+
+// apache 2.0 antlabs
+
+// 树堆, key满足二叉搜索树, 随机的优先级满足大顶堆, 期望高度O(log n)
+// 插入和删除都基于split和merge实现
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/antlabs/gstl/api"
+	"golang.org/x/exp/constraints"
+)
+
+var _ api.SortedMap[int, int] = (*Treap[int, int])(nil)
+
+var ErrOverlap = errors.New("treap: key ranges overlap")
+
+// 元素
+type pair[K constraints.Ordered, V any] struct {
+	val V
+	key K
+}
+
+type node[K constraints.Ordered, V any] struct {
+	left  *node[K, V]
+	right *node[K, V]
+	pair[K, V]
+	priority uint32
+	size     int // 子树的节点个数, Split之后O(1)得到两棵树的长度
+}
+
+func (n *node[K, V]) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *node[K, V]) sizeUpdate() {
+	n.size = n.left.sizeOf() + n.right.sizeOf() + 1
+}
+
+// treap的结构, 零值可以直接使用
+type Treap[K constraints.Ordered, V any] struct {
+	root *node[K, V]
+	r    *rand.Rand
+}
+
+// 构造函数
+func New[K constraints.Ordered, V any]() *Treap[K, V] {
+	t := &Treap[K, V]{}
+	t.lazyinit()
+	return t
+}
+
+// 零值的Treap第一次使用时再创建随机数生成器
+func (t *Treap[K, V]) lazyinit() {
+	if t.r == nil {
+		t.r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+}
+
+// 按k把n分成两棵, left里的key都小于k, right里的key都大于k, 等于k的节点通过mid返回
+func split[K constraints.Ordered, V any](n *node[K, V], k K) (left, mid, right *node[K, V]) {
+	if n == nil {
+		return
+	}
+
+	if k < n.key {
+		left, mid, n.left = split(n.left, k)
+		n.sizeUpdate()
+		return left, mid, n
+	}
+	if k > n.key {
+		n.right, mid, right = split(n.right, k)
+		n.sizeUpdate()
+		return n, mid, right
+	}
+
+	left, right = n.left, n.right
+	n.left, n.right = nil, nil
+	n.size = 1
+	return left, n, right
+}
+
+// 合并两棵树, left里的key都小于right里的key
+func merge[K constraints.Ordered, V any](left, right *node[K, V]) *node[K, V] {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+
+	if left.priority > right.priority {
+		left.right = merge(left.right, right)
+		left.sizeUpdate()
+		return left
+	}
+	right.left = merge(left, right.left)
+	right.sizeUpdate()
+	return right
+}
+
+// Get
+func (t *Treap[K, V]) Get(k K) (v V) {
+	v, _ = t.TryGet(k)
+	return
+}
+
+// 从treap找到需要的值
+func (t *Treap[K, V]) TryGet(k K) (v V, ok bool) {
+	n := t.root
+	for n != nil {
+		if n.key == k {
+			return n.val, true
+		}
+
+		if k > n.key {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return
+}
+
+func (t *Treap[K, V]) Set(k K, v V) {
+	_, _ = t.Swap(k, v)
+}
+
+// 设置接口, 如果有值, 把prev值带返回, 并且被替换, 没有就新加
+func (t *Treap[K, V]) Swap(k K, v V) (prev V, replaced bool) {
+	for n := t.root; n != nil; {
+		if n.key == k {
+			prev = n.val
+			n.val = v
+			return prev, true
+		}
+		if k > n.key {
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+
+	t.lazyinit()
+	n := &node[K, V]{pair: pair[K, V]{key: k, val: v}, priority: t.r.Uint32(), size: 1}
+	t.root = t.insert(t.root, n)
+	return
+}
+
+// 沿着搜索路径往下, 在第一个优先级比n小的位置把子树split开, n挂在那里
+func (t *Treap[K, V]) insert(root, n *node[K, V]) *node[K, V] {
+	if root == nil {
+		return n
+	}
+	if n.priority > root.priority {
+		n.left, _, n.right = split(root, n.key)
+		n.sizeUpdate()
+		return n
+	}
+
+	if n.key < root.key {
+		root.left = t.insert(root.left, n)
+	} else {
+		root.right = t.insert(root.right, n)
+	}
+	root.size++
+	return root
+}
+
+func (t *Treap[K, V]) Delete(k K) {
+	t.DeleteWithPrev(k)
+}
+
+// 删除, 返回被删除的值
+func (t *Treap[K, V]) DeleteWithPrev(k K) (prev V, deleted bool) {
+	link := &t.root
+	var path []*node[K, V]
+	for n := *link; n != nil; n = *link {
+		if n.key == k {
+			prev, deleted = n.val, true
+			*link = merge(n.left, n.right)
+			for _, p := range path {
+				p.size--
+			}
+			return
+		}
+
+		path = append(path, n)
+		if k > n.key {
+			link = &n.right
+		} else {
+			link = &n.left
+		}
+	}
+	return
+}
+
+// 按k把树分成两棵, left里的key都小于k, right里的key都大于k, 期望O(log n)
+// 如果k存在, 通过found返回它的值, ok为true
+// Split之后t变成空树, 节点被left和right复用
+func (t *Treap[K, V]) Split(k K) (left, right *Treap[K, V], found V, ok bool) {
+	t.lazyinit()
+	l, mid, r := split(t.root, k)
+	if mid != nil {
+		found, ok = mid.val, true
+	}
+	t.root = nil
+	return &Treap[K, V]{root: l, r: t.r}, &Treap[K, V]{root: r, r: t.r}, found, ok
+}
+
+// 合并两棵树, left里的key必须都小于right里的key, 否则返回ErrOverlap, 期望O(log n)
+// 合并之后left和right变成空树
+func Merge[K constraints.Ordered, V any](left, right *Treap[K, V]) (*Treap[K, V], error) {
+	if left.root != nil && right.root != nil && left.last().key >= right.first().key {
+		return nil, ErrOverlap
+	}
+
+	left.lazyinit()
+	t := &Treap[K, V]{root: merge(left.root, right.root), r: left.r}
+	left.root, right.root = nil, nil
+	return t, nil
+}
+
+func (t *Treap[K, V]) first() *node[K, V] {
+	n := t.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (t *Treap[K, V]) last() *node[K, V] {
+	n := t.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// 第一个节点
+func (t *Treap[K, V]) First() (v V, ok bool) {
+	if n := t.first(); n != nil {
+		return n.val, true
+	}
+	return
+}
+
+// 最后一个节点
+func (t *Treap[K, V]) Last() (v V, ok bool) {
+	if n := t.last(); n != nil {
+		return n.val, true
+	}
+	return
+}
+
+func (n *node[K, V]) rangeInner(callback func(k K, v V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if !n.left.rangeInner(callback) {
+		return false
+	}
+	if !callback(n.key, n.val) {
+		return false
+	}
+	return n.right.rangeInner(callback)
+}
+
+func (n *node[K, V]) rangePrevInner(callback func(k K, v V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if !n.right.rangePrevInner(callback) {
+		return false
+	}
+	if !callback(n.key, n.val) {
+		return false
+	}
+	return n.left.rangePrevInner(callback)
+}
+
+// 遍历treap
+func (t *Treap[K, V]) Range(callback func(k K, v V) bool) {
+	t.root.rangeInner(callback)
+}
+
+// 倒序遍历treap
+func (t *Treap[K, V]) RangePrev(callback func(k K, v V) bool) {
+	t.root.rangePrevInner(callback)
+}
+
+// 返回最大的n个值, 降序返回, 10, 9, 8, 7
+func (t *Treap[K, V]) TopMax(limit int, callback func(k K, v V) bool) {
+	t.RangePrev(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+// 返回最小的n个值, 升序返回, 比如0,1,2,3
+func (t *Treap[K, V]) TopMin(limit int, callback func(k K, v V) bool) {
+	t.Range(func(k K, v V) bool {
+		if limit <= 0 {
+			return false
+		}
+
+		if !callback(k, v) {
+			return false
+		}
+
+		limit--
+		return true
+	})
+}
+
+func (t *Treap[K, V]) Len() int {
+	return t.root.sizeOf()
+}
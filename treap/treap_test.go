@@ -0,0 +1,187 @@
+package treap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// 检查key的顺序, 堆序和size, 返回节点个数
+func checkTreap(t *testing.T, n *node[int, int], lo, hi *int) int {
+	if n == nil {
+		return 0
+	}
+	if lo != nil && n.key <= *lo || hi != nil && n.key >= *hi {
+		t.Fatalf("key %d out of order", n.key)
+	}
+	for _, c := range []*node[int, int]{n.left, n.right} {
+		if c != nil && c.priority > n.priority {
+			t.Fatalf("key %d priority %d > parent %d", c.key, c.priority, n.priority)
+		}
+	}
+	size := checkTreap(t, n.left, lo, &n.key) + checkTreap(t, n.right, &n.key, hi) + 1
+	if size != n.size {
+		t.Fatalf("key %d size %d, want %d", n.key, n.size, size)
+	}
+	return size
+}
+
+func keysOf(tr *Treap[int, int]) (keys []int) {
+	tr.Range(func(k, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return
+}
+
+func Test_Treap_Random(t *testing.T) {
+	tr := New[int, int]()
+	want := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(2000)
+		switch r.Intn(3) {
+		case 0, 1:
+			prev, replaced := tr.Swap(k, i)
+			old, ok := want[k]
+			if replaced != ok || prev != old {
+				t.Fatalf("swap %d got (%d, %v), want (%d, %v)", k, prev, replaced, old, ok)
+			}
+			want[k] = i
+		case 2:
+			prev, deleted := tr.DeleteWithPrev(k)
+			old, ok := want[k]
+			if deleted != ok || prev != old {
+				t.Fatalf("delete %d got (%d, %v), want (%d, %v)", k, prev, deleted, old, ok)
+			}
+			delete(want, k)
+		}
+
+		k = r.Intn(2000)
+		v, ok := tr.TryGet(k)
+		if old, has := want[k]; ok != has || v != old {
+			t.Fatalf("get %d got (%d, %v)", k, v, ok)
+		}
+	}
+
+	if n := checkTreap(t, tr.root, nil, nil); n != tr.Len() || n != len(want) {
+		t.Fatalf("nodes %d, len %d, want %d", n, tr.Len(), len(want))
+	}
+
+	keys := make([]int, 0, len(want))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	if got := keysOf(tr); !equalSlices(got, keys) {
+		t.Fatalf("range got %v", got)
+	}
+	i := len(keys)
+	tr.RangePrev(func(k, v int) bool {
+		i--
+		if k != keys[i] {
+			t.Fatalf("range prev got %d, want %d", k, keys[i])
+		}
+		return true
+	})
+}
+
+func Test_Treap_SplitMerge(t *testing.T) {
+	tr := New[int, int]()
+	for i := 0; i < 100; i++ {
+		tr.Set(i*2, i)
+	}
+
+	left, right, found, ok := tr.Split(50)
+	if !ok || found != 25 || left.Len() != 25 || right.Len() != 74 || tr.Len() != 0 {
+		t.Fatalf("split(50) got found=%d ok=%v len=%d,%d", found, ok, left.Len(), right.Len())
+	}
+	checkTreap(t, left.root, nil, nil)
+	checkTreap(t, right.root, nil, nil)
+	if v, _ := left.Last(); v != 24 {
+		t.Fatalf("left last %d", v)
+	}
+	if v, _ := right.First(); v != 26 {
+		t.Fatalf("right first %d", v)
+	}
+
+	if _, err := Merge(right, left); err != ErrOverlap {
+		t.Fatalf("merge err %v, want %v", err, ErrOverlap)
+	}
+
+	right.Set(50, 25)
+	m, err := Merge(left, right)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != 100 || left.Len() != 0 || right.Len() != 0 {
+		t.Fatalf("merge len %d", m.Len())
+	}
+	checkTreap(t, m.root, nil, nil)
+	for i := 0; i < 100; i++ {
+		if v, ok := m.TryGet(i * 2); !ok || v != i {
+			t.Fatalf("get %d got (%d, %v)", i*2, v, ok)
+		}
+	}
+
+	_, _, _, ok = m.Split(51)
+	if ok {
+		t.Fatal("split(51) found")
+	}
+}
+
+func Test_Treap_Top(t *testing.T) {
+	tr := New[int, int]()
+	for i := 100; i >= 0; i-- {
+		tr.Set(i, i)
+	}
+
+	var got []int
+	tr.TopMin(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	tr.TopMax(3, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if want := []int{0, 1, 2, 100, 99, 98}; !equalSlices(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func equalSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// 零值可以直接使用, Split和Merge也一样
+func Test_Treap_ZeroValue(t *testing.T) {
+	var tr Treap[int, int]
+	for i := 0; i < 100; i++ {
+		tr.Set(i, i)
+	}
+	if tr.Len() != 100 || tr.Get(50) != 50 {
+		t.Fatalf("len %d, get %d", tr.Len(), tr.Get(50))
+	}
+	checkTreap(t, tr.root, nil, nil)
+
+	var empty Treap[int, int]
+	left, right, _, _ := empty.Split(1)
+	left.Set(0, 0)
+	right.Set(2, 2)
+	m, err := Merge(&Treap[int, int]{}, right)
+	if err != nil || m.Len() != 1 {
+		t.Fatalf("merge len %d, err %v", m.Len(), err)
+	}
+	m.Set(3, 3)
+	checkTreap(t, m.root, nil, nil)
+}
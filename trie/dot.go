@@ -0,0 +1,46 @@
+package trie
+
+// apache 2.0 antlabs
+import (
+	"io"
+	"sort"
+
+	"github.com/antlabs/gstl/dot"
+	idot "github.com/antlabs/gstl/internal/dot"
+)
+
+// 输出graphviz dot格式, 边上显示字符, 有值的节点画成双圈并显示完整的key
+// 孩子按字符排序, 每次输出的结果都一样
+func (t *Trie[V]) WriteDOT(w io.Writer, opts *dot.Options[string, V]) error {
+	dw := idot.NewWriter(w, opts)
+	dw.Printf("digraph trie {")
+	t.writeDOT(dw, nil, 0)
+	dw.Printf("}")
+	return dw.Err()
+}
+
+// 返回节点名
+func (t *Trie[V]) writeDOT(w *idot.Writer[string, V], path []rune, depth int) string {
+	if w.Truncated(depth) {
+		return w.Ellipsis()
+	}
+
+	id := w.NextID()
+	if t.isSet {
+		w.Printf("\t%s [label=%s, shape=doublecircle];", id, idot.Quote(w.Label(string(path), t.v)))
+	} else {
+		w.Printf("\t%s [label=\"\", shape=circle];", id)
+	}
+
+	runes := make([]rune, 0, len(t.children))
+	for r := range t.children {
+		runes = append(runes, r)
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	for _, r := range runes {
+		cid := t.children[r].writeDOT(w, append(path, r), depth+1)
+		w.Printf("\t%s -> %s [label=%s];", id, cid, idot.Quote(string(r)))
+	}
+	return id
+}
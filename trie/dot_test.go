@@ -0,0 +1,35 @@
+package trie
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/antlabs/gstl/dot"
+)
+
+func Test_Trie_WriteDOT(t *testing.T) {
+	tr := New[int]()
+	tr.Set("ab", 1)
+	tr.Set("ac", 2)
+	tr.Set("b", 3)
+
+	var buf bytes.Buffer
+	opts := &dot.Options[string, int]{FormatValue: func(v int) string { return string(rune('0' + v)) }}
+	if err := tr.WriteDOT(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "digraph trie {") || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("bad output:\n%s", out)
+	}
+	for _, s := range []string{`"ab: 1"`, `"ac: 2"`, `"b: 3"`, `[label="a"]`} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("missing %s:\n%s", s, out)
+		}
+	}
+	if n := strings.Count(out, "->"); n != 4 {
+		t.Fatalf("got %d edges:\n%s", n, out)
+	}
+}
@@ -109,13 +109,16 @@ func (v *Vec[T]) Pop() (e T, ok bool) {
 
 	slice := v.ToSlice()
 	e = slice[l-1]
-	v = New(slice[:l-1]...)
+	// 清掉尾部引用, 方便gc
+	var zero T
+	slice[l-1] = zero
+	*v = Vec[T](slice[:l-1])
 
 	// 缩容
 	if v.Len()*2 < v.Cap() {
 		newSlice := make([]T, v.Len())
 		copy(newSlice, slice)
-		v = New(newSlice...)
+		*v = Vec[T](newSlice)
 	}
 
 	return e, true
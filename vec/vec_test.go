@@ -26,6 +26,38 @@ func Test_New_Push_Pop_String(t *testing.T) {
 	}
 }
 
+// 连续pop, 测试长度变化, 弹空之后返回false
+func Test_Pop_Len(t *testing.T) {
+	v := New(1, 2, 3, 4, 5, 6, 7, 8)
+	for want := 8; want > 0; want-- {
+		n, ok := v.Pop()
+		if !ok || n != want {
+			t.Fatalf("Expected (%d, true), got (%v, %v)", want, n, ok)
+		}
+		if v.Len() != want-1 {
+			t.Fatalf("Expected len %d, got %d", want-1, v.Len())
+		}
+	}
+
+	if _, ok := v.Pop(); ok {
+		t.Errorf("Expected pop on empty vec to fail")
+	}
+}
+
+// pop之后底层数组不再引用被弹出的元素
+func Test_Pop_ClearTail(t *testing.T) {
+	a, b := new(int), new(int)
+	v := New(a, b)
+	v.Push(new(int))
+	v.Pop()
+	tail := v.ToSlice()[:v.Cap()]
+	for i := v.Len(); i < len(tail); i++ {
+		if tail[i] != nil {
+			t.Fatalf("index %d still referenced after Pop", i)
+		}
+	}
+}
+
 // push一个slice, pop 1个, 测试string类型
 func Test_New_Push_Slice_Pop_String(t *testing.T) {
 	v := New("1", "2", "3")